@@ -0,0 +1,170 @@
+//go:build linux
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// maxCacheEntries bounds each cache map; expired entries are swept when it is
+// reached and the map is reset if that does not free enough space.
+const maxCacheEntries = 100000
+
+type attrEntry struct {
+	info    *pb.FileInfo
+	expires time.Time
+}
+
+type dirEntry struct {
+	entries []*pb.FileInfo
+	byName  map[string]*pb.FileInfo
+	expires time.Time
+}
+
+// metaCache caches file attributes by path and directory listings by
+// directory path. Entries expire after their TTL and are dropped early when
+// the Watch stream reports a change.
+type metaCache struct {
+	attrTTL  time.Duration
+	entryTTL time.Duration
+
+	mu    sync.Mutex
+	attrs map[string]attrEntry
+	dirs  map[string]dirEntry
+}
+
+func newMetaCache(attrTTL, entryTTL time.Duration) *metaCache {
+	return &metaCache{
+		attrTTL:  attrTTL,
+		entryTTL: entryTTL,
+		attrs:    make(map[string]attrEntry),
+		dirs:     make(map[string]dirEntry),
+	}
+}
+
+func (c *metaCache) getAttr(path string) (*pb.FileInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.attrs[path]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.attrs, path)
+		return nil, false
+	}
+	return e.info, true
+}
+
+func (c *metaCache) putAttr(path string, info *pb.FileInfo) {
+	if c.attrTTL <= 0 || info == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.attrs) >= maxCacheEntries {
+		sweepExpired(c.attrs, func(e attrEntry) time.Time { return e.expires })
+	}
+	c.attrs[path] = attrEntry{info: info, expires: time.Now().Add(c.attrTTL)}
+}
+
+// getDir returns a cached listing of dir.
+func (c *metaCache) getDir(dir string) ([]*pb.FileInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.dirs[dir]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.dirs, dir)
+		return nil, false
+	}
+	return e.entries, true
+}
+
+func (c *metaCache) putDir(dir string, entries []*pb.FileInfo) {
+	if c.entryTTL <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.dirs) >= maxCacheEntries {
+		sweepExpired(c.dirs, func(e dirEntry) time.Time { return e.expires })
+	}
+	byName := make(map[string]*pb.FileInfo, len(entries))
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+	c.dirs[dir] = dirEntry{entries: entries, byName: byName, expires: time.Now().Add(c.entryTTL)}
+}
+
+// lookupDir reports whether a fresh listing of dir exists and, if so, returns
+// name's entry from it (nil if absent). It lets Lookup answer without a round
+// trip: ENOENT for missing names, the listed attributes otherwise.
+func (c *metaCache) lookupDir(dir, name string) (info *pb.FileInfo, listed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.dirs[dir]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.byName[name], true
+}
+
+// invalidate drops the attributes of path and the listing of its parent.
+func (c *metaCache) invalidate(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.attrs, path)
+	delete(c.dirs, path)
+	delete(c.dirs, parentDir(path))
+}
+
+// invalidateTree drops path, everything below it and the listing of its parent.
+func (c *metaCache) invalidateTree(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefix := path + "/"
+	for p := range c.attrs {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(c.attrs, p)
+		}
+	}
+	for p := range c.dirs {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(c.dirs, p)
+		}
+	}
+	delete(c.dirs, parentDir(path))
+}
+
+// clear drops everything, e.g. after events may have been lost.
+func (c *metaCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attrs = make(map[string]attrEntry)
+	c.dirs = make(map[string]dirEntry)
+}
+
+func sweepExpired[E any](m map[string]E, expires func(E) time.Time) {
+	now := time.Now()
+	for k, e := range m {
+		if now.After(expires(e)) {
+			delete(m, k)
+		}
+	}
+	if len(m) >= maxCacheEntries {
+		clear(m)
+	}
+}
+
+// parentDir returns the share-relative parent of path, "." for top-level entries.
+func parentDir(path string) string {
+	return filepath.Dir(path)
+}
@@ -0,0 +1,114 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// dirPageSize is the number of entries requested per ReadDir page.
+const dirPageSize = 1024
+
+// maxCachedListing is the largest listing kept in the metadata cache;
+// bigger directories are streamed without being retained.
+const maxCachedListing = 50000
+
+// dirPageTimeout bounds fetching a follow-up page; the FUSE request that
+// opened the directory is gone by then.
+const dirPageTimeout = 30 * time.Second
+
+// remoteDirStream lists a directory page by page as the kernel consumes it,
+// so large directories are neither held in memory at once nor fetched
+// completely when a reader stops early. A complete listing of moderate size
+// is cached.
+type remoteDirStream struct {
+	node   *fuseFS
+	dir    string
+	cursor string         // Continues the listing; empty after the last page
+	page   []*pb.FileInfo // Fetched but not yet returned
+	listed []*pb.FileInfo // Everything fetched so far, for the cache
+	tooBig bool           // listed was dropped at maxCachedListing
+	err    error
+}
+
+// openDir returns a stream over dir's entries, from the cache when fresh.
+// The first page is fetched right away so errors surface on opendir.
+func (f *fuseFS) openDir(ctx context.Context, dir string) (fs.DirStream, error) {
+	if entries, ok := f.cache.getDir(dir); ok {
+		dirEntries := make([]fuse.DirEntry, 0, len(entries))
+		for _, info := range entries {
+			dirEntries = append(dirEntries, f.dirEntry(dir, info))
+		}
+		return fs.NewListDirStream(dirEntries), nil
+	}
+	s := &remoteDirStream{node: f, dir: dir}
+	if err := s.fetch(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *remoteDirStream) fetch(ctx context.Context) error {
+	entries, next, err := s.node.client.ReadDir(ctx, s.dir, s.cursor, dirPageSize)
+	if err != nil {
+		return err
+	}
+	s.page = entries
+	s.cursor = next
+	// Readdirplus looks up each entry right after listing it.
+	for _, info := range entries {
+		s.node.cache.putAttr(filepath.Join(s.dir, info.Name), info)
+	}
+	if s.tooBig {
+		return nil
+	}
+	s.listed = append(s.listed, entries...)
+	if len(s.listed) > maxCachedListing {
+		s.listed, s.tooBig = nil, true
+	} else if next == "" {
+		s.node.cache.putDir(s.dir, s.listed)
+	}
+	return nil
+}
+
+func (s *remoteDirStream) HasNext() bool {
+	if len(s.page) == 0 && s.cursor != "" && s.err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), dirPageTimeout)
+		s.err = s.fetch(ctx)
+		cancel()
+	}
+	// A failed fetch is reported by Next.
+	return len(s.page) > 0 || s.err != nil
+}
+
+func (s *remoteDirStream) Next() (fuse.DirEntry, syscall.Errno) {
+	if s.err != nil {
+		return fuse.DirEntry{}, toErrno(s.err)
+	}
+	info := s.page[0]
+	s.page = s.page[1:]
+	return s.node.dirEntry(s.dir, info), 0
+}
+
+// Close releases the server-side cursor if the listing was not read to the
+// end.
+func (s *remoteDirStream) Close() {
+	if s.cursor == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dirPageTimeout)
+	defer cancel()
+	if err := s.node.client.ReleaseDir(ctx, s.dir, s.cursor); err != nil {
+		log.Printf("ReadDir: releasing the listing of %s failed: %v", s.dir, err)
+	}
+	s.cursor = ""
+}
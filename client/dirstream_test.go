@@ -0,0 +1,229 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// newTestMount returns the root node of a mount of srv. The node tree is set
+// up as for a real mount, but nothing is mounted.
+func newTestMount(t *testing.T, srv *fakeServer) *fuseFS {
+	t.Helper()
+	mo := mountOptions{attrTTL: time.Minute, entryTTL: time.Minute}
+	root := newFuseFS(srv.dial(t), "test", mo, newMetaCache(mo.attrTTL, mo.entryTTL), nil)
+	fs.NewNodeFS(root, &fs.Options{})
+	return root
+}
+
+// lookupNode looks up name in dir and returns its node, which is added to
+// the tree as go-fuse does for the kernel.
+func lookupNode(t *testing.T, dir *fuseFS, name string) *fuseFS {
+	t.Helper()
+	var out fuse.EntryOut
+	child, errno := dir.Lookup(context.Background(), name, &out)
+	if errno != 0 {
+		t.Fatalf("Lookup(%q): %v", name, errno)
+	}
+	dir.AddChild(name, child, true)
+	return child.Operations().(*fuseFS)
+}
+
+// readAll drains a directory stream.
+func readAll(t *testing.T, stream fs.DirStream) []fuse.DirEntry {
+	t.Helper()
+	defer stream.Close()
+	var out []fuse.DirEntry
+	for stream.HasNext() {
+		e, errno := stream.Next()
+		if errno != 0 {
+			t.Fatalf("Next after %d entries: %v", len(out), errno)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestReaddirPagination(t *testing.T) {
+	const n = 2*dirPageSize + 452
+	srv := newFakeServer()
+	var entries []*pb.FileInfo
+	for i := 0; i < n; i++ {
+		entries = append(entries, &pb.FileInfo{Name: fmt.Sprintf("f%05d", i), Mode: 0o644, Ino: uint64(100 + i)})
+	}
+	srv.addEntries("big", entries...)
+	root := newTestMount(t, srv)
+	big := lookupNode(t, root, "big")
+
+	stream, errno := big.Readdir(context.Background())
+	if errno != 0 {
+		t.Fatal(errno)
+	}
+	got := readAll(t, stream)
+	if len(got) != n {
+		t.Fatalf("listed %d entries, want %d", len(got), n)
+	}
+	for i, e := range got {
+		if e.Name != entries[i].Name {
+			t.Fatalf("entry %d is %q, want %q", i, e.Name, entries[i].Name)
+		}
+	}
+	if srv.readDirs.Load() != 3 || srv.releases.Load() != 0 {
+		t.Errorf("%d ReadDir calls and %d releases, want 3 pages", srv.readDirs.Load(), srv.releases.Load())
+	}
+	if cached, ok := big.cache.getDir("big"); !ok || len(cached) != n {
+		t.Errorf("complete listing not cached: %d entries, %v", len(cached), ok)
+	}
+
+	// A cached listing is served without further calls
+	stream, _ = big.Readdir(context.Background())
+	if got := readAll(t, stream); len(got) != n || srv.readDirs.Load() != 3 {
+		t.Errorf("second listing: %d entries after %d ReadDir calls", len(got), srv.readDirs.Load())
+	}
+}
+
+func TestReaddirFetchesPagesOnDemand(t *testing.T) {
+	srv := newFakeServer()
+	for i := 0; i < 3*dirPageSize; i++ {
+		srv.addEntries("big", &pb.FileInfo{Name: fmt.Sprintf("f%05d", i)})
+	}
+	root := newTestMount(t, srv)
+	big := lookupNode(t, root, "big")
+
+	stream, errno := big.Readdir(context.Background())
+	if errno != 0 {
+		t.Fatal(errno)
+	}
+	for i := 0; i < dirPageSize+1; i++ {
+		if !stream.HasNext() {
+			t.Fatalf("stream ended after %d entries", i)
+		}
+		stream.Next()
+	}
+	stream.Close()
+	if srv.readDirs.Load() != 2 {
+		t.Errorf("%d ReadDir calls for the first %d entries, want 2", srv.readDirs.Load(), dirPageSize+1)
+	}
+	if srv.releases.Load() != 1 {
+		t.Errorf("listing closed early released %d cursors, want 1", srv.releases.Load())
+	}
+	if _, ok := big.cache.getDir("big"); ok {
+		t.Error("incomplete listing was cached")
+	}
+}
+
+func TestReaddirEntryTypes(t *testing.T) {
+	tests := []struct {
+		info *pb.FileInfo
+		mode uint32
+	}{
+		{&pb.FileInfo{Name: "file", Mode: 0o644, Ino: 10}, syscall.S_IFREG | 0o644},
+		{&pb.FileInfo{Name: "setuid", Mode: 0o4755, Ino: 11}, syscall.S_IFREG | 0o4755},
+		{&pb.FileInfo{Name: "dir", IsDir: true, Mode: 0o755, Ino: 12}, syscall.S_IFDIR | 0o755},
+		{&pb.FileInfo{Name: "link", IsSymlink: true, Mode: 0o777, Ino: 13}, syscall.S_IFLNK | 0o777},
+		// Windows flags directory symlinks as directories too
+		{&pb.FileInfo{Name: "dirlink", IsSymlink: true, IsDir: true, Mode: 0o777, Ino: 14}, syscall.S_IFLNK | 0o777},
+		// Type bits sent by the server are ignored
+		{&pb.FileInfo{Name: "typed", Mode: syscall.S_IFDIR | 0o600, Ino: 15}, syscall.S_IFREG | 0o600},
+		{&pb.FileInfo{Name: "noid", Mode: 0o640}, syscall.S_IFREG | 0o640},
+	}
+	srv := newFakeServer()
+	for _, tt := range tests {
+		srv.addEntries(".", tt.info)
+	}
+	root := newTestMount(t, srv)
+
+	stream, errno := root.Readdir(context.Background())
+	if errno != 0 {
+		t.Fatal(errno)
+	}
+	listed := readAll(t, stream)
+	if len(listed) != len(tests) {
+		t.Fatalf("listed %d entries, want %d", len(listed), len(tests))
+	}
+	for i, tt := range tests {
+		e := listed[i]
+		wantIno := tt.info.Ino
+		if wantIno == 0 {
+			wantIno = root.hashIno(tt.info.Name)
+		}
+		if e.Mode != tt.mode || e.Ino != wantIno {
+			t.Errorf("%s: listed mode %o ino %d, want %o ino %d", tt.info.Name, e.Mode, e.Ino, tt.mode, wantIno)
+		}
+
+		// Lookup must agree with the listing
+		var out fuse.EntryOut
+		child, errno := root.Lookup(context.Background(), tt.info.Name, &out)
+		if errno != 0 {
+			t.Fatalf("Lookup(%q): %v", tt.info.Name, errno)
+		}
+		if out.Attr.Mode != tt.mode {
+			t.Errorf("%s: attr mode %o, want %o", tt.info.Name, out.Attr.Mode, tt.mode)
+		}
+		if st := child.StableAttr(); st.Mode != tt.mode&syscall.S_IFMT || st.Ino != wantIno {
+			t.Errorf("%s: inode type %o ino %d, want %o ino %d", tt.info.Name, st.Mode, st.Ino, tt.mode&syscall.S_IFMT, wantIno)
+		}
+	}
+}
+
+// TestListingAnswersLookups replays what `ls -l big` makes the kernel send
+// with READDIRPLUS: attributes of the mount root and big, the listing, and a
+// lookup and getattr per entry. Only the first two need a Stat.
+func TestListingAnswersLookups(t *testing.T) {
+	const n = 10000
+	srv := newFakeServer()
+	for i := 0; i < n; i++ {
+		srv.addEntries("big", &pb.FileInfo{Name: fmt.Sprintf("f%05d", i), Mode: 0o644, Ino: uint64(100 + i)})
+	}
+	ctx := context.Background()
+
+	lsLong := func(listFirst bool) int32 {
+		t.Helper()
+		root := newTestMount(t, srv)
+		srv.stats.Store(0)
+		var attr fuse.AttrOut
+		if errno := root.Getattr(ctx, nil, &attr); errno != 0 {
+			t.Fatal(errno)
+		}
+		big := lookupNode(t, root, "big")
+		if errno := big.Getattr(ctx, nil, &attr); errno != 0 {
+			t.Fatal(errno)
+		}
+		names := make([]string, 0, n)
+		if listFirst {
+			stream, errno := big.Readdir(ctx)
+			if errno != 0 {
+				t.Fatal(errno)
+			}
+			for _, e := range readAll(t, stream) {
+				names = append(names, e.Name)
+			}
+		} else {
+			for i := 0; i < n; i++ {
+				names = append(names, fmt.Sprintf("f%05d", i))
+			}
+		}
+		for _, name := range names {
+			if errno := lookupNode(t, big, name).Getattr(ctx, nil, &attr); errno != 0 {
+				t.Fatal(errno)
+			}
+		}
+		return srv.stats.Load()
+	}
+
+	if got := lsLong(true); got != 2 {
+		t.Errorf("%d Stat calls after listing %d entries, want 2", got, n)
+	}
+	if got := lsLong(false); got != n+2 {
+		t.Errorf("%d Stat calls without a listing, want %d", got, n+2)
+	}
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// remoteError is a failure reported by the server in a pb.Error message,
+// as opposed to a transport error from gRPC itself.
+type remoteError struct {
+	op        string // RPC that failed, e.g. "stat"
+	code      int32  // POSIX errno
+	win32Code int32  // Original Windows error code, 0 if unknown
+	message   string
+	// unknownHandle is set if the server does not hold the handle the
+	// request named, so the file may be re-opened
+	unknownHandle bool
+}
+
+func newRemoteError(op string, e *pb.Error) *remoteError {
+	return &remoteError{op: op, code: e.Code, win32Code: e.Win32Code, message: e.Message, unknownHandle: e.UnknownHandle}
+}
+
+func (e *remoteError) Error() string {
+	if e.win32Code != 0 {
+		return fmt.Sprintf("%s error %d (win32 %d): %s", e.op, e.code, e.win32Code, e.message)
+	}
+	return fmt.Sprintf("%s error %d: %s", e.op, e.code, e.message)
+}
+
+// Errno returns the POSIX errno carried by the server, or EIO if it sent none.
+func (e *remoteError) Errno() syscall.Errno {
+	if e.code <= 0 {
+		return syscall.EIO
+	}
+	return syscall.Errno(e.code)
+}
+
+// toErrno maps errors returned by grpcClient to the errno reported to FUSE.
+func toErrno(err error) syscall.Errno {
+	if err == nil {
+		return 0
+	}
+
+	var remote *remoteError
+	if errors.As(err, &remote) {
+		return remote.Errno()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return syscall.EINTR
+	case errors.Is(err, context.DeadlineExceeded):
+		return syscall.ETIMEDOUT
+	}
+
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Canceled:
+			return syscall.EINTR
+		case codes.DeadlineExceeded:
+			return syscall.ETIMEDOUT
+		case codes.Unavailable:
+			return syscall.EAGAIN
+		case codes.NotFound:
+			return syscall.ENOENT
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return syscall.EACCES
+		case codes.InvalidArgument:
+			return syscall.EINVAL
+		case codes.ResourceExhausted:
+			return syscall.ENOMEM
+		case codes.Unimplemented:
+			return syscall.ENOSYS
+		}
+	}
+
+	return syscall.EIO
+}
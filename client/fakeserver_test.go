@@ -0,0 +1,256 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// fakeServer is an in-memory FileSystemService for driving the client
+// without a share on disk.
+type fakeServer struct {
+	pb.UnimplementedFileSystemServiceServer
+	maxRead int32
+
+	stats    atomic.Int32 // Stat calls
+	readDirs atomic.Int32 // ReadDir calls, continuations included
+	streams  atomic.Int32 // ReadStream calls
+	opens    atomic.Int32 // Open calls
+	releases atomic.Int32 // ReadDir calls releasing a cursor
+
+	// readErr, if set, fails every Read and ReadStream with it.
+	readErr *pb.Error
+
+	// streamGate, if set, holds back every ReadStream chunk until it
+	// receives a value or is closed.
+	streamGate chan struct{}
+
+	mu       sync.Mutex
+	files    map[string][]byte         // Contents by path
+	infos    map[string]*pb.FileInfo   // Attributes by path
+	listings map[string][]*pb.FileInfo // Directory entries in listing order
+	handles  map[int32]string
+	next     int32
+	chtimes  []*pb.ChtimesRequest // Chtimes calls received
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		maxRead:  1 << 20,
+		files:    make(map[string][]byte),
+		infos:    map[string]*pb.FileInfo{".": {Name: ".", IsDir: true, Mode: 0o755, Ino: 2}},
+		listings: make(map[string][]*pb.FileInfo),
+		handles:  make(map[int32]string),
+	}
+}
+
+// addEntries lists entries in dir, which is created if needed.
+func (s *fakeServer) addEntries(dir string, entries ...*pb.FileInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.infos[dir]; !ok {
+		s.infos[dir] = &pb.FileInfo{Name: filepath.Base(dir), IsDir: true, Mode: 0o755}
+		parent := filepath.Dir(dir)
+		s.listings[parent] = append(s.listings[parent], s.infos[dir])
+	}
+	for _, info := range entries {
+		s.infos[filepath.Join(dir, info.Name)] = info
+		s.listings[dir] = append(s.listings[dir], info)
+	}
+}
+
+// removeEntry deletes the entry at path, leaving any other names of it.
+func (s *fakeServer) removeEntry(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.infos, path)
+	delete(s.files, path)
+	dir := filepath.Dir(path)
+	s.listings[dir] = slices.DeleteFunc(s.listings[dir], func(info *pb.FileInfo) bool {
+		return info.Name == filepath.Base(path)
+	})
+}
+
+// addFile stores a file with the given contents.
+func (s *fakeServer) addFile(path string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = data
+}
+
+// dial serves s on an in-process listener and returns a client for it.
+func (s *fakeServer) dial(tb testing.TB) *grpcClient {
+	tb.Helper()
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(out) })
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterFileSystemServiceServer(srv, s)
+	go srv.Serve(lis)
+	tb.Cleanup(srv.Stop)
+
+	c, err := newGRPCClient("bufconn", grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func (s *fakeServer) GetCapabilities(ctx context.Context, req *pb.CapabilitiesRequest) (*pb.CapabilitiesResponse, error) {
+	limit := s.maxRead
+	if req.MaxReadSize > 0 {
+		limit = min(limit, req.MaxReadSize)
+	}
+	return &pb.CapabilitiesResponse{MaxReadSize: limit}, nil
+}
+
+func (s *fakeServer) Open(ctx context.Context, req *pb.OpenRequest) (*pb.OpenResponse, error) {
+	s.opens.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[req.Path]; !ok {
+		return &pb.OpenResponse{Result: &pb.OpenResponse_Error{Error: &pb.Error{Code: int32(syscall.ENOENT), Message: "no such file"}}}, nil
+	}
+	s.next++
+	s.handles[s.next] = req.Path
+	return &pb.OpenResponse{Result: &pb.OpenResponse_Handle{Handle: s.next}}, nil
+}
+
+func (s *fakeServer) Close(ctx context.Context, req *pb.CloseRequest) (*pb.CloseResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.handles, req.Handle)
+	return &pb.CloseResponse{}, nil
+}
+
+// readAt returns up to size bytes of the file behind handle at off.
+func (s *fakeServer) readAt(handle int32, off int64, size int32) ([]byte, *pb.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path, ok := s.handles[handle]
+	if !ok {
+		return nil, &pb.Error{Code: int32(syscall.EBADF), Message: "unknown handle", UnknownHandle: true}
+	}
+	if s.readErr != nil {
+		return nil, s.readErr
+	}
+	data := s.files[path]
+	if off >= int64(len(data)) {
+		return nil, nil
+	}
+	end := min(off+int64(min(size, s.maxRead)), int64(len(data)))
+	return data[off:end], nil
+}
+
+func (s *fakeServer) Read(ctx context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
+	data, e := s.readAt(req.Handle, req.Offset, req.Size)
+	if e != nil {
+		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: e}}, nil
+	}
+	return &pb.ReadResponse{Result: &pb.ReadResponse_Data{Data: data}}, nil
+}
+
+func (s *fakeServer) ReadStream(req *pb.ReadStreamRequest, stream pb.FileSystemService_ReadStreamServer) error {
+	s.streams.Add(1)
+	chunk := req.ChunkSize
+	if chunk <= 0 {
+		chunk = s.maxRead
+	}
+	off := req.Offset
+	for req.Length == 0 || off < req.Offset+req.Length {
+		size := chunk
+		if req.Length > 0 {
+			size = int32(min(int64(size), req.Offset+req.Length-off))
+		}
+		if s.streamGate != nil {
+			select {
+			case <-s.streamGate:
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			}
+		}
+		data, e := s.readAt(req.Handle, off, size)
+		if e != nil {
+			return stream.Send(&pb.ReadChunk{Offset: off, Result: &pb.ReadChunk_Error{Error: e}})
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		if err := stream.Send(&pb.ReadChunk{Offset: off, Result: &pb.ReadChunk_Data{Data: data}}); err != nil {
+			return err
+		}
+		off += int64(len(data))
+		if len(data) < int(size) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *fakeServer) Stat(ctx context.Context, req *pb.StatRequest) (*pb.StatResponse, error) {
+	s.stats.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.infos[req.Path]
+	if !ok {
+		return &pb.StatResponse{Result: &pb.StatResponse_Error{Error: &pb.Error{Code: int32(syscall.ENOENT), Message: "no such file"}}}, nil
+	}
+	return &pb.StatResponse{Result: &pb.StatResponse_Info{Info: info}}, nil
+}
+
+func (s *fakeServer) Chtimes(ctx context.Context, req *pb.ChtimesRequest) (*pb.ChtimesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chtimes = append(s.chtimes, req)
+	return &pb.ChtimesResponse{}, nil
+}
+
+// ReadDir pages through a listing; the cursor is the index of the next entry.
+func (s *fakeServer) ReadDir(ctx context.Context, req *pb.ReadDirRequest) (*pb.ReadDirResponse, error) {
+	if req.Release {
+		s.releases.Add(1)
+		return &pb.ReadDirResponse{}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readDirs.Add(1)
+	info, ok := s.infos[req.Path]
+	if !ok || !info.IsDir {
+		return &pb.ReadDirResponse{Error: &pb.Error{Code: int32(syscall.ENOENT), Message: "no such directory"}}, nil
+	}
+	entries := s.listings[req.Path]
+	start := 0
+	if req.Cursor != "" {
+		n, err := strconv.Atoi(req.Cursor)
+		if err != nil || n > len(entries) {
+			return &pb.ReadDirResponse{Error: &pb.Error{Code: int32(syscall.EINVAL), Message: "invalid cursor"}}, nil
+		}
+		start = n
+	}
+	end := min(start+int(req.Limit), len(entries))
+	resp := &pb.ReadDirResponse{Entries: entries[start:end], HasMore: end < len(entries)}
+	if resp.HasMore {
+		resp.NextCursor = strconv.Itoa(end)
+	}
+	return resp, nil
+}
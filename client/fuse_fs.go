@@ -19,281 +19,549 @@ import (
 
 type fuseFS struct {
 	fs.Inode
-	client *grpcClient
-	share  string
-	path   string // Current path for this node
+	client      *grpcClient
+	share       string
+	readOnly    bool         // Reject every mutating operation with EROFS
+	streamChunk int32        // Chunk size for streamed sequential reads; 0 disables them
+	readAhead   int64        // Read-ahead window in bytes; 0 disables prefetching
+	cache       *metaCache   // Shared by all nodes of the mount
+	pages       *pageCache   // Shared by all nodes of the mount; nil if disabled
+	nodes       *nodeIndex   // Shared by all nodes of the mount
+	mu          sync.RWMutex // Guards path, which changes on rename
+	path        string       // Current path for this node
 }
 
 // Ensure fuseFS implements the required interfaces
 var _ fs.NodeReaddirer = (*fuseFS)(nil)
+var _ fs.NodeGetattrer = (*fuseFS)(nil)
+var _ fs.NodeOpener = (*fuseFS)(nil)
+var _ fs.NodeCreater = (*fuseFS)(nil)
+var _ fs.NodeSetattrer = (*fuseFS)(nil)
+var _ fs.NodeMkdirer = (*fuseFS)(nil)
+var _ fs.NodeRmdirer = (*fuseFS)(nil)
+var _ fs.NodeUnlinker = (*fuseFS)(nil)
+var _ fs.NodeRenamer = (*fuseFS)(nil)
+var _ fs.NodeReadlinker = (*fuseFS)(nil)
+var _ fs.NodeSymlinker = (*fuseFS)(nil)
+var _ fs.NodeStatfser = (*fuseFS)(nil)
+
+func newFuseFS(client *grpcClient, share string, mo mountOptions, cache *metaCache, pages *pageCache) *fuseFS {
+	return &fuseFS{
+		client:      client,
+		share:       share,
+		readOnly:    mo.readOnly,
+		streamChunk: int32(mo.streamChunk),
+		readAhead:   int64(mo.readAhead),
+		cache:       cache,
+		pages:       pages,
+		nodes:       newNodeIndex(),
+		path:        "", // Root path
+	}
+}
 
-func newFuseFS(client *grpcClient, share string) *fuseFS {
+// newNode returns a node for path sharing the mount-wide settings and caches.
+func (f *fuseFS) newNode(path string) *fuseFS {
 	return &fuseFS{
-		client: client,
-		share:  share,
-		path:   "", // Root path
+		client:      f.client,
+		share:       f.share,
+		readOnly:    f.readOnly,
+		streamChunk: f.streamChunk,
+		readAhead:   f.readAhead,
+		cache:       f.cache,
+		pages:       f.pages,
+		nodes:       f.nodes,
+		path:        path,
 	}
 }
 
-func (f *fuseFS) Getattr(ctx context.Context, fh fs.FileHandle, out *fuse.Attr) syscall.Errno {
+func (f *fuseFS) Getattr(ctx context.Context, fh fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
 	path := f.getPath(ctx)
-	log.Printf("Getattr: path=%s, f.path=%s", path, f.path)
+	log.Printf("Getattr: path=%s", path)
 	if path == "" {
 		log.Printf("Getattr: empty path, returning ENOENT")
 		return syscall.ENOENT
 	}
 
-	info, err := f.client.Stat(ctx, path)
+	info, err := f.stat(ctx, path)
 	if err != nil {
 		log.Printf("Getattr: Stat failed for path=%s, error=%v", path, err)
 		return f.mapError(err)
 	}
 
 	log.Printf("Getattr: Stat success for path=%s, name=%s, isDir=%v", path, info.Name, info.IsDir)
-	f.fillAttr(info, out)
-
-	// Ensure the directory flag is set correctly for FUSE
-	if info.IsDir {
-		out.Mode |= syscall.S_IFDIR
-		log.Printf("Getattr: Set directory flag for path=%s", path)
-	}
-
+	f.fillAttr(info, &out.Attr)
 	return 0
 }
 
-func (f *fuseFS) Open(ctx context.Context, fh fs.FileHandle, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+func (f *fuseFS) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
 	path := f.getPath(ctx)
 	if path == "" {
 		return nil, 0, syscall.ENOENT
 	}
 
+	if f.readOnly && isWriteOpen(flags) {
+		log.Printf("Open: write access to %s on read-only mount, flags=%o", path, flags)
+		return nil, 0, syscall.EROFS
+	}
+
 	handle, err := f.client.Open(ctx, path, int32(flags))
 	if err != nil {
 		return nil, 0, f.mapError(err)
 	}
 
-	return &fuseFile{client: f.client, handle: handle}, 0, 0
+	return f.newFile(handle), 0, 0
 }
 
-func (f *fuseFS) ReadDir(ctx context.Context) (fs.DirStream, syscall.Errno) {
+func (f *fuseFS) Create(ctx context.Context, name string, flags uint32, mode uint32, out *fuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if f.readOnly {
+		return nil, nil, 0, syscall.EROFS
+	}
+
 	path := f.getPath(ctx)
-	log.Printf("=== ReadDir CALLED === path=%s, f.path=%s", path, f.path)
 	if path == "" {
-		log.Printf("ReadDir: empty path, returning ENOENT")
-		return nil, syscall.ENOENT
+		return nil, nil, 0, syscall.ENOENT
 	}
 
-	// For root directory, use "." to get share contents (relative to root)
-	requestPath := "."
-	if f.path != "" {
-		requestPath = f.path
+	childPath := filepath.Join(path, name)
+	log.Printf("Create: childPath=%s, flags=%o, mode=%o", childPath, flags, mode)
+	handle, info, err := f.client.Create(ctx, childPath, int32(flags), mode)
+	if err != nil {
+		log.Printf("Create: failed for childPath=%s, error=%v", childPath, err)
+		return nil, nil, 0, f.mapError(err)
 	}
-	log.Printf("ReadDir: calling gRPC ReadDir with requestPath=%s", requestPath)
 
-	entries, _, err := f.client.ReadDir(ctx, requestPath, 0, 0)
-	if err != nil {
-		log.Printf("ReadDir: gRPC call failed, requestPath=%s, error=%v", requestPath, err)
-		return nil, f.mapError(err)
+	f.cache.invalidate(childPath)
+	f.cache.putAttr(childPath, info)
+	child := f.newChild(ctx, childPath, info)
+
+	f.fillAttr(info, &out.Attr)
+	return child, child.Operations().(*fuseFS).newFile(handle), 0, 0
+}
+
+func (f *fuseFS) Setattr(ctx context.Context, fh fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	if f.readOnly {
+		return syscall.EROFS
 	}
-	log.Printf("ReadDir: gRPC success, found %d entries", len(entries))
 
-	dirEntries := make([]fuse.DirEntry, 0, len(entries))
-	for _, info := range entries {
-		mode := uint32(0o644)
-		if info.IsDir {
-			mode = 0o755
+	path := f.getPath(ctx)
+	if path == "" {
+		return syscall.ENOENT
+	}
+
+	// The server has no permission bits or owners to change; requests that
+	// would leave them as reported are accepted, others refused.
+	_, setMode := in.GetMode()
+	_, setUID := in.GetUID()
+	_, setGID := in.GetGID()
+	if setMode || setUID || setGID {
+		info, err := f.stat(ctx, path)
+		if err != nil {
+			return f.mapError(err)
+		}
+		if mode, ok := in.GetMode(); ok && mode != info.Mode&0o7777 {
+			return syscall.EPERM
+		}
+		if uid, ok := in.GetUID(); ok && uid != info.Uid {
+			return syscall.EPERM
+		}
+		if gid, ok := in.GetGID(); ok && gid != info.Gid {
+			return syscall.EPERM
 		}
-		if info.IsSymlink {
-			mode |= syscall.S_IFLNK
-		} else if info.IsDir {
-			mode |= syscall.S_IFDIR
-		} else {
-			mode |= syscall.S_IFREG
+	}
+
+	if size, ok := in.GetSize(); ok {
+		var handle *remoteFile
+		if file, ok := fh.(*fuseFile); ok {
+			handle = file.file
+		}
+		log.Printf("Setattr: truncate path=%s, open=%v, size=%d", path, handle != nil, size)
+		if err := f.client.Truncate(ctx, path, handle, int64(size)); err != nil {
+			return f.mapError(err)
 		}
+	}
 
-		log.Printf("ReadDir: adding entry %s (isDir=%v, mode=%o)", info.Name, info.IsDir, mode)
-		dirEntries = append(dirEntries, fuse.DirEntry{
-			Name: info.Name,
-			Mode: mode,
-		})
+	atime, setAtime := in.GetATime()
+	mtime, setMtime := in.GetMTime()
+	if setAtime || setMtime {
+		log.Printf("Setattr: chtimes path=%s, atime=%v, mtime=%v", path, setAtime, setMtime)
+		err := f.client.Chtimes(ctx, path,
+			timeChange{set: setAtime, now: in.Valid&fuse.FATTR_ATIME_NOW != 0, t: atime},
+			timeChange{set: setMtime, now: in.Valid&fuse.FATTR_MTIME_NOW != 0, t: mtime})
+		if err != nil {
+			return f.mapError(err)
+		}
 	}
 
-	log.Printf("ReadDir: returning %d directory entries", len(dirEntries))
-	return fs.NewListDirStream(dirEntries), 0
+	f.cache.invalidate(path)
+	f.invalidatePages()
+	info, err := f.stat(ctx, path)
+	if err != nil {
+		return f.mapError(err)
+	}
+	f.fillAttr(info, &out.Attr)
+	return 0
 }
 
-// Readdir implements the NodeReaddirer interface
+// Readdir implements the NodeReaddirer interface. It serves both READDIR and
+// READDIRPLUS: go-fuse looks up each entry of the stream for the latter,
+// which Lookup answers from the listing.
 func (f *fuseFS) Readdir(ctx context.Context) (fs.DirStream, syscall.Errno) {
-	log.Printf("=== Readdir CALLED === (NodeReaddirer interface)")
 	path := f.getPath(ctx)
-	log.Printf("Readdir: path=%s, f.path=%s", path, f.path)
+	log.Printf("Readdir: path=%s", path)
 	if path == "" {
 		log.Printf("Readdir: empty path, returning ENOENT")
 		return nil, syscall.ENOENT
 	}
 
-	// For root directory, use "." to get share contents (relative to root)
-	requestPath := "."
-	if f.path != "" {
-		requestPath = f.path
-	}
-	log.Printf("Readdir: calling gRPC ReadDir with requestPath=%s", requestPath)
-
-	entries, _, err := f.client.ReadDir(ctx, requestPath, 0, 0)
+	// For root directory, getPath returns "." to get share contents (relative to root)
+	stream, err := f.openDir(ctx, path)
 	if err != nil {
-		log.Printf("Readdir: gRPC call failed, requestPath=%s, error=%v", requestPath, err)
+		log.Printf("Readdir: gRPC call failed, requestPath=%s, error=%v", path, err)
 		return nil, f.mapError(err)
 	}
-	log.Printf("Readdir: gRPC success, found %d entries", len(entries))
+	return stream, 0
+}
 
-	dirEntries := make([]fuse.DirEntry, 0, len(entries))
-	for _, info := range entries {
-		mode := uint32(0o644)
-		if info.IsDir {
-			mode = 0o755
-		}
-		if info.IsSymlink {
-			mode |= syscall.S_IFLNK
-		} else if info.IsDir {
-			mode |= syscall.S_IFDIR
-		} else {
-			mode |= syscall.S_IFREG
+// dirEntry converts a listed entry of dir for the kernel. Mode and inode
+// match what Lookup and Getattr report for the same entry.
+func (f *fuseFS) dirEntry(dir string, info *pb.FileInfo) fuse.DirEntry {
+	return fuse.DirEntry{
+		Name: info.Name,
+		Mode: f.modeFromInfo(info),
+		Ino:  f.inoFor(filepath.Join(dir, info.Name), info),
+	}
+}
+
+func (f *fuseFS) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	path := f.getPath(ctx)
+	log.Printf("Lookup: path=%s, name=%s", path, name)
+	if path == "" {
+		log.Printf("Lookup: empty path, returning ENOENT")
+		return nil, syscall.ENOENT
+	}
+
+	childPath := filepath.Join(path, name)
+	// Readdirplus looks up every listed entry; answer from the listing.
+	info, listed := f.cache.lookupDir(path, name)
+	if listed && info == nil {
+		log.Printf("Lookup: %s not in cached listing of %s", name, path)
+		return nil, syscall.ENOENT
+	}
+	if info == nil {
+		log.Printf("Lookup: calling Stat for childPath=%s", childPath)
+		var err error
+		info, err = f.stat(ctx, childPath)
+		if err != nil {
+			log.Printf("Lookup: Stat failed for childPath=%s, error=%v", childPath, err)
+			return nil, f.mapError(err)
 		}
+		log.Printf("Lookup: Stat success for childPath=%s, name=%s, isDir=%v", childPath, info.Name, info.IsDir)
+	}
+
+	child := f.newChild(ctx, childPath, info)
+
+	f.fillAttr(info, &out.Attr)
+	return child, 0
+}
 
-		log.Printf("Readdir: adding entry %s (isDir=%v, mode=%o)", info.Name, info.IsDir, mode)
-		dirEntries = append(dirEntries, fuse.DirEntry{
-			Name: info.Name,
-			Mode: mode,
-		})
+func (f *fuseFS) Mkdir(ctx context.Context, name string, mode uint32, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if f.readOnly {
+		return nil, syscall.EROFS
 	}
 
-	log.Printf("Readdir: returning %d directory entries", len(dirEntries))
-	return fs.NewListDirStream(dirEntries), 0
+	path := f.getPath(ctx)
+	if path == "" {
+		return nil, syscall.ENOENT
+	}
+
+	childPath := filepath.Join(path, name)
+	log.Printf("Mkdir: childPath=%s, mode=%o", childPath, mode)
+	info, err := f.client.Mkdir(ctx, childPath, mode)
+	if err != nil {
+		log.Printf("Mkdir: failed for childPath=%s, error=%v", childPath, err)
+		return nil, f.mapError(err)
+	}
+
+	f.cache.invalidate(childPath)
+	f.cache.putAttr(childPath, info)
+	child := f.newChild(ctx, childPath, info)
+	f.fillAttr(info, &out.Attr)
+	return child, 0
 }
 
-func (f *fuseFS) ReadDirPlus(ctx context.Context, fh fs.FileHandle, entries *fuse.DirEntryList) syscall.Errno {
+func (f *fuseFS) Rmdir(ctx context.Context, name string) syscall.Errno {
+	if f.readOnly {
+		return syscall.EROFS
+	}
+
 	path := f.getPath(ctx)
-	log.Printf("=== ReadDirPlus CALLED === path=%s, f.path=%s", path, f.path)
 	if path == "" {
-		log.Printf("ReadDirPlus: empty path, returning ENOENT")
 		return syscall.ENOENT
 	}
 
-	// For root directory, use "." to get share contents (relative to root)
-	requestPath := "."
-	if f.path != "" {
-		requestPath = f.path
+	childPath := filepath.Join(path, name)
+	log.Printf("Rmdir: childPath=%s", childPath)
+	if err := f.client.Rmdir(ctx, childPath); err != nil {
+		log.Printf("Rmdir: failed for childPath=%s, error=%v", childPath, err)
+		return f.mapError(err)
 	}
-	log.Printf("ReadDirPlus: calling gRPC ReadDir with requestPath=%s", requestPath)
+	f.cache.invalidateTree(childPath)
+	return 0
+}
 
-	grpcEntries, _, err := f.client.ReadDir(ctx, requestPath, 0, 0)
-	if err != nil {
-		log.Printf("ReadDirPlus: gRPC call failed, requestPath=%s, error=%v", requestPath, err)
+func (f *fuseFS) Unlink(ctx context.Context, name string) syscall.Errno {
+	if f.readOnly {
+		return syscall.EROFS
+	}
+
+	path := f.getPath(ctx)
+	if path == "" {
+		return syscall.ENOENT
+	}
+
+	childPath := filepath.Join(path, name)
+	log.Printf("Unlink: childPath=%s", childPath)
+	if err := f.client.Unlink(ctx, childPath); err != nil {
+		log.Printf("Unlink: failed for childPath=%s, error=%v", childPath, err)
 		return f.mapError(err)
 	}
-	log.Printf("ReadDirPlus: gRPC success, found %d entries", len(grpcEntries))
+	f.cache.invalidate(childPath)
+	return 0
+}
 
-	for _, info := range grpcEntries {
-		mode := uint32(0o644)
-		if info.IsDir {
-			mode = 0o755
+// Rename moves the child name to newParent/newName. go-fuse moves the inodes
+// once this returns; the node paths are updated here to match.
+func (f *fuseFS) Rename(ctx context.Context, name string, newParent fs.InodeEmbedder, newName string, flags uint32) syscall.Errno {
+	if f.readOnly {
+		return syscall.EROFS
+	}
+
+	path := f.getPath(ctx)
+	if path == "" {
+		return syscall.ENOENT
+	}
+	target, ok := newParent.(*fuseFS)
+	if !ok {
+		return syscall.EXDEV
+	}
+
+	oldPath := filepath.Join(path, name)
+	newPath := filepath.Join(target.getPath(ctx), newName)
+	log.Printf("Rename: oldPath=%s, newPath=%s, flags=%d", oldPath, newPath, flags)
+	if err := f.client.Rename(ctx, oldPath, newPath, flags); err != nil {
+		log.Printf("Rename: failed oldPath=%s, newPath=%s, error=%v", oldPath, newPath, err)
+		return f.mapError(err)
+	}
+	f.cache.invalidateTree(oldPath)
+	f.cache.invalidateTree(newPath)
+	f.client.renameOpenFiles(oldPath, newPath, flags&fs.RENAME_EXCHANGE != 0)
+
+	moved := f.GetChild(name)
+	var swapped *fs.Inode
+	if flags&fs.RENAME_EXCHANGE != 0 {
+		swapped = target.GetChild(newName)
+	}
+	if moved != nil {
+		if node, ok := moved.Operations().(*fuseFS); ok {
+			node.setPath(newPath)
 		}
-		if info.IsSymlink {
-			mode |= syscall.S_IFLNK
-		} else if info.IsDir {
-			mode |= syscall.S_IFDIR
-		} else {
-			mode |= syscall.S_IFREG
+	}
+	if swapped != nil {
+		if node, ok := swapped.Operations().(*fuseFS); ok {
+			node.setPath(oldPath)
 		}
+	}
+	return 0
+}
 
-		log.Printf("ReadDirPlus: adding entry %s (isDir=%v, mode=%o)", info.Name, info.IsDir, mode)
-
-		// Create child inode for the entry
-		childPath := filepath.Join(path, info.Name)
-		child := f.NewInode(ctx, &fuseFS{client: f.client, share: f.share, path: childPath}, fs.StableAttr{
-			Mode: mode,
-			Ino:  f.hashIno(childPath),
-		})
-
-		// Add to directory entry list
-		if !entries.AddDirEntry(fuse.DirEntry{
-			Name: info.Name,
-			Mode: mode,
-			Ino:  child.StableAttr().Ino,
-		}) {
-			log.Printf("ReadDirPlus: entries list full, stopping")
-			break
-		}
+func (f *fuseFS) Readlink(ctx context.Context) ([]byte, syscall.Errno) {
+	path := f.getPath(ctx)
+	if path == "" {
+		return nil, syscall.ENOENT
 	}
 
-	log.Printf("ReadDirPlus: returning %d directory entries", len(grpcEntries))
-	return 0
+	target, err := f.client.Readlink(ctx, path)
+	if err != nil {
+		log.Printf("Readlink: failed for path=%s, error=%v", path, err)
+		return nil, f.mapError(err)
+	}
+	return []byte(target), 0
 }
 
-func (f *fuseFS) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+// Symlink creates a link to target. The server only accepts relative targets
+// that stay within the share.
+func (f *fuseFS) Symlink(ctx context.Context, target, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if f.readOnly {
+		return nil, syscall.EROFS
+	}
+
 	path := f.getPath(ctx)
-	log.Printf("Lookup: path=%s, name=%s", path, name)
 	if path == "" {
-		log.Printf("Lookup: empty path, returning ENOENT")
 		return nil, syscall.ENOENT
 	}
 
 	childPath := filepath.Join(path, name)
-	log.Printf("Lookup: calling Stat for childPath=%s", childPath)
-	info, err := f.client.Stat(ctx, childPath)
+	log.Printf("Symlink: childPath=%s, target=%s", childPath, target)
+	info, err := f.client.Symlink(ctx, childPath, target)
 	if err != nil {
-		log.Printf("Lookup: Stat failed for childPath=%s, error=%v", childPath, err)
+		log.Printf("Symlink: failed for childPath=%s, error=%v", childPath, err)
 		return nil, f.mapError(err)
 	}
-	log.Printf("Lookup: Stat success for childPath=%s, name=%s, isDir=%v", childPath, info.Name, info.IsDir)
-
-	child := f.NewInode(ctx, &fuseFS{client: f.client, share: f.share, path: childPath}, fs.StableAttr{
-		Mode: f.modeFromInfo(info),
-		Ino:  f.hashIno(childPath),
-	})
 
+	f.cache.invalidate(childPath)
+	f.cache.putAttr(childPath, info)
+	child := f.newChild(ctx, childPath, info)
 	f.fillAttr(info, &out.Attr)
 	return child, 0
 }
 
+// Statfs reports the capacity of the share's volume, so df and free-space
+// checks see real figures.
+func (f *fuseFS) Statfs(ctx context.Context, out *fuse.StatfsOut) syscall.Errno {
+	path := f.getPath(ctx)
+	if path == "" {
+		return syscall.ENOENT
+	}
+
+	stats, err := f.client.Statfs(ctx, path)
+	if err != nil {
+		log.Printf("Statfs: failed for path=%s, error=%v", path, err)
+		return f.mapError(err)
+	}
+
+	bsize := uint64(stats.BlockSize)
+	if bsize == 0 {
+		bsize = 4096
+	}
+	out.Bsize = uint32(bsize)
+	out.Frsize = uint32(bsize)
+	out.Blocks = stats.TotalBytes / bsize
+	out.Bfree = stats.FreeBytes / bsize
+	out.Bavail = stats.AvailableBytes / bsize
+	out.Files = stats.TotalFiles
+	out.Ffree = stats.FreeFiles
+	out.NameLen = stats.MaxNameLength
+	return 0
+}
+
 func (f *fuseFS) getPath(ctx context.Context) string {
 	// Return the current path for this node
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 	if f.path == "" {
 		return "." // Root directory - use relative path
 	}
 	return f.path
 }
 
+// setPath re-roots this node and every known descendant at path after a rename.
+func (f *fuseFS) setPath(path string) {
+	f.mu.Lock()
+	f.path = path
+	f.mu.Unlock()
+
+	for name, child := range f.Children() {
+		if node, ok := child.Operations().(*fuseFS); ok {
+			node.setPath(filepath.Join(path, name))
+		}
+	}
+}
+
+// stat returns the attributes of path, from the cache when fresh.
+func (f *fuseFS) stat(ctx context.Context, path string) (*pb.FileInfo, error) {
+	if info, ok := f.cache.getAttr(path); ok {
+		return info, nil
+	}
+	info, err := f.client.Stat(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	f.cache.putAttr(path, info)
+	return info, nil
+}
+
+// newChild builds the inode for a child entry of this directory, reusing
+// the node the file already has. If the file was renamed behind our back,
+// that node is re-rooted at its new path; if its old path still names it,
+// childPath is another hard link and the node keeps the path it has.
+func (f *fuseFS) newChild(ctx context.Context, childPath string, info *pb.FileInfo) *fs.Inode {
+	mode, ino := f.modeFromInfo(info), f.inoFor(childPath, info)
+	node := f.nodes.get(ino, mode, f.newNode(childPath))
+	if oldPath := node.getPath(ctx); oldPath != childPath && !f.stillAt(ctx, oldPath, info) {
+		log.Printf("newChild: file ID %d moved from %s to %s", info.Ino, oldPath, childPath)
+		node.setPath(childPath)
+		f.client.renameOpenFiles(oldPath, childPath, false)
+	}
+	return f.NewInode(ctx, node, fs.StableAttr{Mode: mode, Ino: ino})
+}
+
+// stillAt reports whether path names the file described by info on the
+// server.
+func (f *fuseFS) stillAt(ctx context.Context, path string, info *pb.FileInfo) bool {
+	cur, err := f.client.Stat(ctx, path)
+	return err == nil && cur.Ino == info.Ino && cur.Dev == info.Dev
+}
+
+// invalidatePages drops the node's cached file contents.
+func (f *fuseFS) invalidatePages() {
+	if f.pages != nil {
+		f.pages.invalidate(f.StableAttr().Ino)
+	}
+}
+
 func (f *fuseFS) fillAttr(info *pb.FileInfo, out *fuse.Attr) {
 	out.Size = uint64(info.Size)
 	out.Mode = f.modeFromInfo(info)
 	out.Uid = info.Uid
 	out.Gid = info.Gid
 	out.Atime = uint64(info.AccessTime)
+	out.Atimensec = info.AccessTimeNsec
 	out.Mtime = uint64(info.ModTime)
+	out.Mtimensec = info.ModTimeNsec
 	out.Ctime = uint64(info.ChangeTime)
+	out.Ctimensec = info.ChangeTimeNsec
 	out.Nlink = 1
-
-	// Ensure directory flag is set for directories
-	if info.IsDir {
-		out.Mode |= syscall.S_IFDIR
-		log.Printf("fillAttr: Set directory flag for %s", info.Name)
+	if info.Nlink > 0 {
+		out.Nlink = info.Nlink
 	}
 }
 
+// modeFromInfo returns the file type and permission bits of an entry. A
+// symlink is reported as such even if the server also flags it as a
+// directory, as Windows does for directory symlinks.
 func (f *fuseFS) modeFromInfo(info *pb.FileInfo) uint32 {
-	mode := info.Mode
-	if info.IsDir {
-		mode |= syscall.S_IFDIR
-		log.Printf("modeFromInfo: Set directory flag for %s", info.Name)
-	} else if info.IsSymlink {
+	mode := info.Mode & 0o7777
+	switch {
+	case info.IsSymlink:
 		mode |= syscall.S_IFLNK
-	} else {
+	case info.IsDir:
+		mode |= syscall.S_IFDIR
+	default:
 		mode |= syscall.S_IFREG
 	}
 	return mode
 }
 
+// isWriteOpen reports whether open flags request write access or would modify the file.
+func isWriteOpen(flags uint32) bool {
+	if flags&syscall.O_ACCMODE != syscall.O_RDONLY {
+		return true
+	}
+	return flags&(syscall.O_CREAT|syscall.O_TRUNC|syscall.O_APPEND) != 0
+}
+
+// inoFor returns the inode number for an entry: the file ID reported by the
+// server, so identity survives renames and hard links share an inode. Servers
+// that report no ID fall back to a path hash. IDs are not combined with
+// info.Dev, so a share spanning several volumes may see collisions.
+func (f *fuseFS) inoFor(path string, info *pb.FileInfo) uint64 {
+	// Inode 1 is reserved for the mount root.
+	if info.Ino > 1 {
+		return info.Ino
+	}
+	return f.hashIno(path)
+}
+
 func (f *fuseFS) hashIno(path string) uint64 {
 	// Simple hash for inode number
 	hash := uint64(0)
@@ -304,26 +572,51 @@ func (f *fuseFS) hashIno(path string) uint64 {
 }
 
 func (f *fuseFS) mapError(err error) syscall.Errno {
-	// Map gRPC errors to syscall errors
-	if err == nil {
-		return 0
-	}
-	// For now, return generic I/O error
-	// TODO: Parse gRPC error details for better mapping
-	return syscall.EIO
+	// Map server errnos and gRPC status codes to syscall errors
+	return toErrno(err)
 }
 
 type fuseFile struct {
 	client *grpcClient
-	handle int32
-	mu     sync.Mutex
+	node   *fuseFS     // Node the file was opened on, for cache invalidation
+	file   *remoteFile // Server handle, restored across reconnects
+	seq    *seqReader  // Streams sequential reads when the page cache is disabled
+	ra     *readAhead  // Reads through the page cache; nil if it is disabled
+}
+
+func (f *fuseFS) newFile(handle *remoteFile) *fuseFile {
+	file := &fuseFile{client: f.client, node: f, file: handle}
+	if f.pages != nil {
+		file.ra = newReadAhead(f.client, handle, f.pages, f.readAhead, f.streamChunk)
+	} else {
+		file.seq = newSeqReader(f.client, handle, f.streamChunk)
+	}
+	return file
 }
 
+var _ fs.FileReader = (*fuseFile)(nil)
+var _ fs.FileWriter = (*fuseFile)(nil)
+var _ fs.FileReleaser = (*fuseFile)(nil)
+
+// Read may run concurrently for one handle; the server reads positionally.
 func (f *fuseFile) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+	if f.ra != nil {
+		info, err := f.node.stat(ctx, f.node.getPath(ctx))
+		if err != nil {
+			return nil, f.mapError(err)
+		}
+		v := pageVersion{id: f.node.StableAttr().Ino, mtime: info.ModTime*1e9 + int64(info.ModTimeNsec)}
+		data, err := f.ra.read(ctx, v, dest, off)
+		if err != nil {
+			return nil, f.mapError(err)
+		}
+		return fuse.ReadResultData(data), 0
+	}
+	if data, ok := f.seq.read(dest, off); ok {
+		return fuse.ReadResultData(data), 0
+	}
 
-	data, err := f.client.Read(ctx, f.handle, off, int32(len(dest)))
+	data, err := f.client.Read(ctx, f.file, off, int32(len(dest)))
 	if err != nil {
 		return nil, f.mapError(err)
 	}
@@ -331,8 +624,27 @@ func (f *fuseFile) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadR
 	return fuse.ReadResultData(data), 0
 }
 
+func (f *fuseFile) Write(ctx context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	if f.seq != nil {
+		f.seq.reset()
+	}
+	n, err := f.client.Write(ctx, f.file, off, data)
+	f.node.cache.invalidate(f.node.getPath(ctx))
+	f.node.invalidatePages()
+	if err != nil {
+		return 0, f.mapError(err)
+	}
+
+	return uint32(n), 0
+}
+
 func (f *fuseFile) Release(ctx context.Context) syscall.Errno {
-	err := f.client.CloseHandle(ctx, f.handle)
+	if f.ra != nil {
+		f.ra.close()
+	} else {
+		f.seq.reset()
+	}
+	err := f.client.CloseHandle(ctx, f.file)
 	if err != nil {
 		log.Printf("close handle error: %v", err)
 		return f.mapError(err)
@@ -341,11 +653,7 @@ func (f *fuseFile) Release(ctx context.Context) syscall.Errno {
 }
 
 func (f *fuseFile) mapError(err error) syscall.Errno {
-	if err == nil {
-		return 0
-	}
-	// TODO: Better error mapping
-	return syscall.EIO
+	return toErrno(err)
 }
 
 // Prevent unused import warnings
@@ -0,0 +1,81 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+func TestHardLinkKeepsNodePath(t *testing.T) {
+	srv := newFakeServer()
+	srv.addEntries(".",
+		&pb.FileInfo{Name: "a", Mode: 0o644, Ino: 500, Dev: 7, Nlink: 2},
+		&pb.FileInfo{Name: "b", Mode: 0o644, Ino: 500, Dev: 7, Nlink: 2},
+	)
+	root := newTestMount(t, srv)
+
+	a := lookupNode(t, root, "a")
+	if b := lookupNode(t, root, "b"); b != a {
+		t.Fatal("second link got its own node")
+	}
+	if p := a.getPath(context.Background()); p != "a" {
+		t.Errorf("looking up a second link moved the node to %q", p)
+	}
+
+	// Once the first name is gone, the node follows the file
+	srv.removeEntry("a")
+	srv.addEntries(".", &pb.FileInfo{Name: "c", Mode: 0o644, Ino: 500, Dev: 7, Nlink: 2})
+	lookupNode(t, root, "c")
+	if p := a.getPath(context.Background()); p != "c" {
+		t.Errorf("node of a renamed file left at %q, want c", p)
+	}
+}
+
+func TestSetattr(t *testing.T) {
+	srv := newFakeServer()
+	srv.addEntries(".", &pb.FileInfo{Name: "f", Mode: 0o644, Ino: 500})
+	root := newTestMount(t, srv)
+	f := lookupNode(t, root, "f")
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		in   fuse.SetAttrInCommon
+		want syscall.Errno
+		sent *pb.ChtimesRequest // nil if no Chtimes is expected
+	}{
+		{"mtime", fuse.SetAttrInCommon{Valid: fuse.FATTR_MTIME, Mtime: 1600000000, Mtimensec: 5},
+			0, &pb.ChtimesRequest{Path: "f", SetMtime: true, Mtime: 1600000000, MtimeNsec: 5}},
+		{"touch", fuse.SetAttrInCommon{Valid: fuse.FATTR_ATIME | fuse.FATTR_ATIME_NOW | fuse.FATTR_MTIME | fuse.FATTR_MTIME_NOW},
+			0, &pb.ChtimesRequest{Path: "f", SetAtime: true, AtimeNow: true, SetMtime: true, MtimeNow: true}},
+		{"same mode", fuse.SetAttrInCommon{Valid: fuse.FATTR_MODE, Mode: 0o644}, 0, nil},
+		{"chmod", fuse.SetAttrInCommon{Valid: fuse.FATTR_MODE | fuse.FATTR_MTIME, Mode: 0o600}, syscall.EPERM, nil},
+		{"chown", fuse.SetAttrInCommon{Valid: fuse.FATTR_UID, Owner: fuse.Owner{Uid: 1000}}, syscall.EPERM, nil},
+		{"chgrp", fuse.SetAttrInCommon{Valid: fuse.FATTR_GID, Owner: fuse.Owner{Gid: 1000}}, syscall.EPERM, nil},
+	}
+	for _, tt := range tests {
+		srv.mu.Lock()
+		srv.chtimes = nil
+		srv.mu.Unlock()
+		var out fuse.AttrOut
+		if errno := f.Setattr(ctx, nil, &fuse.SetAttrIn{SetAttrInCommon: tt.in}, &out); errno != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, errno, tt.want)
+		}
+		srv.mu.Lock()
+		sent := srv.chtimes
+		srv.mu.Unlock()
+		switch {
+		case tt.sent == nil && len(sent) > 0:
+			t.Errorf("%s: sent %v", tt.name, sent)
+		case tt.sent != nil && (len(sent) != 1 || !proto.Equal(sent[0], tt.sent)):
+			t.Errorf("%s: sent %v, want %v", tt.name, sent, tt.sent)
+		}
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/example/fsdriver/proto"
 )
@@ -18,16 +21,26 @@ import (
 type grpcClient struct {
 	conn   *grpc.ClientConn
 	client pb.FileSystemServiceClient
-	mu     sync.RWMutex
+	mu     sync.RWMutex // Held for writing while handles are restored after a reconnect
+	cancel context.CancelFunc
+
+	maxRead atomic.Int32 // Largest read the server serves, see negotiate
+
+	filesMu   sync.Mutex
+	files     map[*remoteFile]struct{} // Open handles to restore after a reconnect
+	resyncFns []func()
 }
 
-func newGRPCClient(addr string) (*grpcClient, error) {
+// newGRPCClient connects to the server at addr. Extra dial options are
+// applied after the defaults, e.g. to dial an in-process listener.
+func newGRPCClient(addr string, extra ...grpc.DialOption) (*grpcClient, error) {
 	log.Printf("Connecting to server at %s", addr)
-	conn, err := grpc.Dial(addr,
+	conn, err := grpc.Dial(addr, append([]grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),                 // Wait for connection to be ready
-		grpc.WithTimeout(10*time.Second), // Connection timeout
-	)
+		grpc.WithBlock(),                   // Wait for connection to be ready
+		grpc.WithTimeout(10 * time.Second), // Connection timeout
+		grpc.WithConnectParams(reconnectParams),
+	}, extra...)...)
 	if err != nil {
 		log.Printf("Failed to connect to server: %v", err)
 		return nil, fmt.Errorf("dial server: %w", err)
@@ -35,13 +48,59 @@ func newGRPCClient(addr string) (*grpcClient, error) {
 	log.Printf("gRPC connection established to %s", addr)
 
 	log.Printf("Successfully connected to server at %s", addr)
-	return &grpcClient{
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &grpcClient{
 		conn:   conn,
 		client: pb.NewFileSystemServiceClient(conn),
-	}, nil
+		cancel: cancel,
+		files:  make(map[*remoteFile]struct{}),
+	}
+	c.maxRead.Store(fallbackMaxRead)
+	negotiateCtx, negotiateCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer negotiateCancel()
+	if err := c.negotiate(negotiateCtx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("negotiate capabilities: %w", err)
+	}
+	go c.monitor(ctx)
+	return c, nil
+}
+
+// Read size limits.
+const (
+	// proposedMaxRead is the largest read the client asks for; it keeps
+	// responses under gRPC's default 4 MiB receive limit.
+	proposedMaxRead = 2 << 20
+	// fallbackMaxRead is used with servers that predate GetCapabilities.
+	fallbackMaxRead = 128 << 10
+)
+
+// negotiate agrees on the maximum read size with the server. It runs after
+// every (re)connect since a restarted server may use other limits.
+func (c *grpcClient) negotiate(ctx context.Context) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.GetCapabilities(ctx, &pb.CapabilitiesRequest{MaxReadSize: proposedMaxRead})
+	if status.Code(err) == codes.Unimplemented {
+		log.Printf("Server does not report capabilities, limiting reads to %d bytes", fallbackMaxRead)
+		c.maxRead.Store(fallbackMaxRead)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if resp.MaxReadSize <= 0 {
+		return fmt.Errorf("server reported invalid max read size %d", resp.MaxReadSize)
+	}
+	c.maxRead.Store(resp.MaxReadSize)
+	log.Printf("Negotiated max read size %d bytes", resp.MaxReadSize)
+	return nil
 }
 
 func (c *grpcClient) Close() error {
+	c.cancel()
 	return c.conn.Close()
 }
 
@@ -65,7 +124,7 @@ func (c *grpcClient) TestConnection(ctx context.Context) error {
 		}
 		log.Printf("Connection test successful - server root is accessible (name: %s)", result.Info.Name)
 	case *pb.StatResponse_Error:
-		return fmt.Errorf("server returned error: %d - %s", result.Error.Code, result.Error.Message)
+		return fmt.Errorf("server returned error: %w", newRemoteError("stat", result.Error))
 	default:
 		return fmt.Errorf("unexpected server response")
 	}
@@ -87,95 +146,405 @@ func (c *grpcClient) Stat(ctx context.Context, path string) (*pb.FileInfo, error
 	case *pb.StatResponse_Info:
 		return result.Info, nil
 	case *pb.StatResponse_Error:
-		return nil, fmt.Errorf("stat error %d: %s", result.Error.Code, result.Error.Message)
+		return nil, newRemoteError("stat", result.Error)
 	default:
 		return nil, fmt.Errorf("unexpected stat response")
 	}
 }
 
-func (c *grpcClient) ReadDir(ctx context.Context, path string, offset, limit int32) ([]*pb.FileInfo, bool, error) {
+// ReadDir fetches one page of at most limit entries of path. An empty cursor
+// starts the listing; the returned cursor continues it and is empty after
+// the last page.
+func (c *grpcClient) ReadDir(ctx context.Context, path, cursor string, limit int32) ([]*pb.FileInfo, string, error) {
 	c.mu.RLock()
 	client := c.client
 	c.mu.RUnlock()
 
-	log.Printf("gRPC ReadDir call: path=%s, offset=%d, limit=%d", path, offset, limit)
+	log.Printf("gRPC ReadDir call: path=%s, continued=%v, limit=%d", path, cursor != "", limit)
 
 	resp, err := client.ReadDir(ctx, &pb.ReadDirRequest{
 		Path:   path,
-		Offset: offset,
 		Limit:  limit,
+		Cursor: cursor,
 	})
 	if err != nil {
 		log.Printf("gRPC ReadDir call failed: %v", err)
-		return nil, false, err
+		return nil, "", err
 	}
 
 	if resp.Error != nil {
 		log.Printf("gRPC ReadDir server error: %d - %s", resp.Error.Code, resp.Error.Message)
-		return nil, false, fmt.Errorf("readdir error %d: %s", resp.Error.Code, resp.Error.Message)
+		return nil, "", newRemoteError("readdir", resp.Error)
 	}
 
 	log.Printf("gRPC ReadDir call successful: %d entries, hasMore=%v", len(resp.Entries), resp.HasMore)
-	return resp.Entries, resp.HasMore, nil
+	if !resp.HasMore {
+		return resp.Entries, "", nil
+	}
+	return resp.Entries, resp.NextCursor, nil
 }
 
-func (c *grpcClient) Open(ctx context.Context, path string, flags int32) (int32, error) {
+// ReleaseDir ends a listing before its last page so the server can close
+// the cursor instead of waiting for it to expire.
+func (c *grpcClient) ReleaseDir(ctx context.Context, path, cursor string) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.ReadDir(ctx, &pb.ReadDirRequest{Path: path, Cursor: cursor, Release: true})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return newRemoteError("readdir", resp.Error)
+	}
+	return nil
+}
+
+func (c *grpcClient) Open(ctx context.Context, path string, flags int32) (*remoteFile, error) {
 	c.mu.RLock()
 	client := c.client
 	c.mu.RUnlock()
 
 	resp, err := client.Open(ctx, &pb.OpenRequest{Path: path, Flags: flags})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	switch result := resp.Result.(type) {
 	case *pb.OpenResponse_Handle:
-		return result.Handle, nil
+		return c.track(result.Handle, path, flags), nil
 	case *pb.OpenResponse_Error:
-		return 0, fmt.Errorf("open error %d: %s", result.Error.Code, result.Error.Message)
+		return nil, newRemoteError("open", result.Error)
 	default:
-		return 0, fmt.Errorf("unexpected open response")
+		return nil, fmt.Errorf("unexpected open response")
 	}
 }
 
-func (c *grpcClient) Read(ctx context.Context, handle int32, offset int64, size int32) ([]byte, error) {
+// Read reads size bytes at offset, split into requests of at most the
+// negotiated maximum. A short result means end of file.
+func (c *grpcClient) Read(ctx context.Context, file *remoteFile, offset int64, size int32) ([]byte, error) {
+	var buf []byte
+	for {
+		data, err := c.readChunk(ctx, file, offset+int64(len(buf)), min(size-int32(len(buf)), c.maxRead.Load()))
+		if err != nil {
+			return nil, err
+		}
+		if buf == nil && len(data) == int(size) {
+			return data, nil // Served by a single request
+		}
+		buf = append(buf, data...)
+		if len(data) == 0 || len(buf) == int(size) {
+			return buf, nil
+		}
+	}
+}
+
+// readChunk issues a single Read; the server may return fewer than size bytes.
+func (c *grpcClient) readChunk(ctx context.Context, file *remoteFile, offset int64, size int32) ([]byte, error) {
+	var data []byte
+	err := c.withHandle(ctx, file, func(client pb.FileSystemServiceClient, handle int32) error {
+		resp, err := client.Read(ctx, &pb.ReadRequest{
+			Handle: handle,
+			Offset: offset,
+			Size:   size,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch result := resp.Result.(type) {
+		case *pb.ReadResponse_Data:
+			data = result.Data
+			return nil
+		case *pb.ReadResponse_Error:
+			return newRemoteError("read", result.Error)
+		default:
+			return fmt.Errorf("unexpected read response")
+		}
+	})
+	return data, err
+}
+
+// ReadStream starts streaming length bytes of file from offset (0 = to EOF)
+// in chunks of about chunkSize bytes, capped at the negotiated maximum.
+// Cancelling ctx ends the stream.
+func (c *grpcClient) ReadStream(ctx context.Context, file *remoteFile, offset, length int64, chunkSize int32) (pb.FileSystemService_ReadStreamClient, error) {
 	c.mu.RLock()
 	client := c.client
 	c.mu.RUnlock()
 
-	resp, err := client.Read(ctx, &pb.ReadRequest{
-		Handle: handle,
-		Offset: offset,
-		Size:   size,
+	return client.ReadStream(ctx, &pb.ReadStreamRequest{
+		Handle:    file.handle(),
+		Offset:    offset,
+		Length:    length,
+		ChunkSize: min(chunkSize, c.maxRead.Load()),
 	})
+}
+
+func (c *grpcClient) CloseHandle(ctx context.Context, file *remoteFile) error {
+	c.untrack(file)
+
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Close(ctx, &pb.CloseRequest{Handle: file.handle()})
+	if err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return newRemoteError("close", resp.Error)
+	}
+
+	return nil
+}
+
+func (c *grpcClient) Create(ctx context.Context, path string, flags int32, mode uint32) (*remoteFile, *pb.FileInfo, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Create(ctx, &pb.CreateRequest{Path: path, Flags: flags, Mode: mode})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, nil, newRemoteError("create", resp.Error)
+	}
+
+	return c.track(resp.Handle, path, flags), resp.Info, nil
+}
+
+func (c *grpcClient) Write(ctx context.Context, file *remoteFile, offset int64, data []byte) (int32, error) {
+	var written int32
+	err := c.withHandle(ctx, file, func(client pb.FileSystemServiceClient, handle int32) error {
+		resp, err := client.Write(ctx, &pb.WriteRequest{
+			Handle: handle,
+			Offset: offset,
+			Data:   data,
+		})
+		if err != nil {
+			return err
+		}
+
+		switch result := resp.Result.(type) {
+		case *pb.WriteResponse_Written:
+			written = result.Written
+			return nil
+		case *pb.WriteResponse_Error:
+			return newRemoteError("write", result.Error)
+		default:
+			return fmt.Errorf("unexpected write response")
+		}
+	})
+	return written, err
+}
+
+// Truncate resizes a file; file is used instead of path when non-nil.
+func (c *grpcClient) Truncate(ctx context.Context, path string, file *remoteFile, size int64) error {
+	truncate := func(client pb.FileSystemServiceClient, handle int32) error {
+		resp, err := client.Truncate(ctx, &pb.TruncateRequest{Path: path, Handle: handle, Size: size})
+		if err != nil {
+			return err
+		}
+
+		if resp.Error != nil {
+			return newRemoteError("truncate", resp.Error)
+		}
+
+		return nil
+	}
+
+	if file != nil {
+		return c.withHandle(ctx, file, truncate)
+	}
+
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+	return truncate(client, 0)
+}
+
+// timeChange is one time of a Chtimes call: left alone unless set, and the
+// server's current time if now.
+type timeChange struct {
+	set, now bool
+	t        time.Time
+}
+
+func (c *grpcClient) Chtimes(ctx context.Context, path string, atime, mtime timeChange) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	req := &pb.ChtimesRequest{
+		Path:     path,
+		SetAtime: atime.set,
+		AtimeNow: atime.now,
+		SetMtime: mtime.set,
+		MtimeNow: mtime.now,
+	}
+	if atime.set && !atime.now {
+		req.Atime, req.AtimeNsec = atime.t.Unix(), uint32(atime.t.Nanosecond())
+	}
+	if mtime.set && !mtime.now {
+		req.Mtime, req.MtimeNsec = mtime.t.Unix(), uint32(mtime.t.Nanosecond())
+	}
+	resp, err := client.Chtimes(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return newRemoteError("chtimes", resp.Error)
+	}
+	return nil
+}
+
+func (c *grpcClient) Mkdir(ctx context.Context, path string, mode uint32) (*pb.FileInfo, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Mkdir(ctx, &pb.MkdirRequest{Path: path, Mode: mode})
 	if err != nil {
 		return nil, err
 	}
 
 	switch result := resp.Result.(type) {
-	case *pb.ReadResponse_Data:
-		return result.Data, nil
-	case *pb.ReadResponse_Error:
-		return nil, fmt.Errorf("read error %d: %s", result.Error.Code, result.Error.Message)
+	case *pb.MkdirResponse_Info:
+		return result.Info, nil
+	case *pb.MkdirResponse_Error:
+		return nil, newRemoteError("mkdir", result.Error)
 	default:
-		return nil, fmt.Errorf("unexpected read response")
+		return nil, fmt.Errorf("unexpected mkdir response")
 	}
 }
 
-func (c *grpcClient) CloseHandle(ctx context.Context, handle int32) error {
+func (c *grpcClient) Rmdir(ctx context.Context, path string) error {
 	c.mu.RLock()
 	client := c.client
 	c.mu.RUnlock()
 
-	resp, err := client.Close(ctx, &pb.CloseRequest{Handle: handle})
+	resp, err := client.Rmdir(ctx, &pb.RmdirRequest{Path: path})
 	if err != nil {
 		return err
 	}
 
 	if resp.Error != nil {
-		return fmt.Errorf("close error %d: %s", resp.Error.Code, resp.Error.Message)
+		return newRemoteError("rmdir", resp.Error)
 	}
 
 	return nil
 }
+
+func (c *grpcClient) Unlink(ctx context.Context, path string) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Unlink(ctx, &pb.UnlinkRequest{Path: path})
+	if err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return newRemoteError("unlink", resp.Error)
+	}
+
+	return nil
+}
+
+// Rename moves oldPath to newPath; flags are Linux renameat2(2) flags.
+func (c *grpcClient) Rename(ctx context.Context, oldPath, newPath string, flags uint32) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Rename(ctx, &pb.RenameRequest{OldPath: oldPath, NewPath: newPath, Flags: flags})
+	if err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return newRemoteError("rename", resp.Error)
+	}
+
+	return nil
+}
+
+// Readlink returns the target of the symbolic link at path, relative to the
+// link's directory.
+func (c *grpcClient) Readlink(ctx context.Context, path string) (string, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Readlink(ctx, &pb.ReadlinkRequest{Path: path})
+	if err != nil {
+		return "", err
+	}
+
+	switch result := resp.Result.(type) {
+	case *pb.ReadlinkResponse_Target:
+		return result.Target, nil
+	case *pb.ReadlinkResponse_Error:
+		return "", newRemoteError("readlink", result.Error)
+	default:
+		return "", fmt.Errorf("unexpected readlink response")
+	}
+}
+
+// Symlink creates a link at path pointing to target and returns its attributes.
+func (c *grpcClient) Symlink(ctx context.Context, path, target string) (*pb.FileInfo, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Symlink(ctx, &pb.SymlinkRequest{Path: path, Target: target})
+	if err != nil {
+		return nil, err
+	}
+
+	switch result := resp.Result.(type) {
+	case *pb.SymlinkResponse_Info:
+		return result.Info, nil
+	case *pb.SymlinkResponse_Error:
+		return nil, newRemoteError("symlink", result.Error)
+	default:
+		return nil, fmt.Errorf("unexpected symlink response")
+	}
+}
+
+// Statfs returns capacity and usage of the volume holding path on the server.
+func (c *grpcClient) Statfs(ctx context.Context, path string) (*pb.FsStats, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	resp, err := client.Statfs(ctx, &pb.StatfsRequest{Path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	switch result := resp.Result.(type) {
+	case *pb.StatfsResponse_Stats:
+		return result.Stats, nil
+	case *pb.StatfsResponse_Error:
+		return nil, newRemoteError("statfs", result.Error)
+	default:
+		return nil, fmt.Errorf("unexpected statfs response")
+	}
+}
+
+// Watch opens a change notification stream; subscriptions are sent on the
+// returned stream as WatchRequest messages.
+func (c *grpcClient) Watch(ctx context.Context) (pb.FileSystemService_WatchClient, error) {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	return client.Watch(ctx)
+}
@@ -0,0 +1,81 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+const benchFileSize = 16 << 20
+
+// benchFile serves a file of benchFileSize bytes and opens it.
+func benchFile(b *testing.B) (*grpcClient, *remoteFile) {
+	b.Helper()
+	srv := newFakeServer()
+	data := make([]byte, benchFileSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	srv.addFile("f", data)
+	c := srv.dial(b)
+	file, err := c.Open(context.Background(), "f", 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c, file
+}
+
+// BenchmarkUnaryRead reads the file one page-sized Read at a time, as cache
+// misses do.
+func BenchmarkUnaryRead(b *testing.B) {
+	c, file := benchFile(b)
+	ctx := context.Background()
+	b.SetBytes(benchFileSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for off := int64(0); off < benchFileSize; off += pageSize {
+			data, err := c.Read(ctx, file, off, pageSize)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(data) != pageSize {
+				b.Fatalf("short read at %d: %d bytes", off, len(data))
+			}
+		}
+	}
+}
+
+// BenchmarkReadStream reads the file through one ReadStream in chunks of the
+// default --stream-chunk size.
+func BenchmarkReadStream(b *testing.B) {
+	c, file := benchFile(b)
+	b.SetBytes(benchFileSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := c.ReadStream(ctx, file, 0, 0, 256<<10)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n := 0
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+			if e := chunk.GetError(); e != nil {
+				b.Fatal(e)
+			}
+			n += len(chunk.GetData())
+		}
+		cancel()
+		if n != benchFileSize {
+			b.Fatalf("streamed %d bytes, want %d", n, benchFileSize)
+		}
+	}
+}
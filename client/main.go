@@ -4,18 +4,34 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 )
 
+// mountOptions carries the tunables passed from the command line to mountRemote.
+type mountOptions struct {
+	readOnly    bool
+	attrTTL     time.Duration
+	entryTTL    time.Duration
+	streamChunk int
+	pageCache   int
+	readAhead   int
+}
+
 func main() {
 	var share string
 	var mountpoint string
 	var addr string
-	var readOnly bool
+	var opts mountOptions
 
 	flag.StringVar(&share, "share", "", "Share name or path exposed by server")
 	flag.StringVar(&mountpoint, "mountpoint", "", "Mount point (Linux)")
 	flag.StringVar(&addr, "addr", "127.0.0.1:50051", "server address")
-	flag.BoolVar(&readOnly, "ro", true, "mount read-only")
+	flag.BoolVar(&opts.readOnly, "ro", true, "mount read-only")
+	flag.DurationVar(&opts.attrTTL, "attr-ttl", time.Second, "how long file attributes are cached; changes reported by the server invalidate earlier")
+	flag.DurationVar(&opts.entryTTL, "entry-ttl", time.Second, "how long directory entries are cached; changes reported by the server invalidate earlier")
+	flag.IntVar(&opts.streamChunk, "stream-chunk", 256<<10, "chunk size in bytes for streaming sequential reads (0 = one request per read)")
+	flag.IntVar(&opts.pageCache, "page-cache", 64<<20, "size in bytes of the client page cache (0 disables it and read-ahead)")
+	flag.IntVar(&opts.readAhead, "read-ahead", 4<<20, "bytes to prefetch ahead of sequential readers (0 disables read-ahead)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
@@ -58,7 +74,7 @@ func main() {
 		os.Exit(2)
 	}
 
-	if err := mountRemote(share, mountpoint, addr, readOnly); err != nil {
+	if err := mountRemote(share, mountpoint, addr, opts); err != nil {
 		fmt.Fprintf(os.Stderr, "Mount error: %v\n", err)
 		fmt.Fprintf(os.Stderr, "\nTroubleshooting:\n")
 		fmt.Fprintf(os.Stderr, "1. Ensure the server is running: server.exe --share <path>\n")
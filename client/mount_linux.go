@@ -15,8 +15,9 @@ import (
 	"github.com/hanwen/go-fuse/v2/fuse"
 )
 
-func mountRemote(share, mountpoint, addr string, readOnly bool) error {
-	log.Printf("Starting mount: share=%s, mountpoint=%s, addr=%s, ro=%v", share, mountpoint, addr, readOnly)
+func mountRemote(share, mountpoint, addr string, mo mountOptions) error {
+	log.Printf("Starting mount: share=%s, mountpoint=%s, addr=%s, ro=%v, attr-ttl=%v, entry-ttl=%v",
+		share, mountpoint, addr, mo.readOnly, mo.attrTTL, mo.entryTTL)
 
 	// Create gRPC client
 	client, err := newGRPCClient(addr)
@@ -41,20 +42,27 @@ func mountRemote(share, mountpoint, addr string, readOnly bool) error {
 	log.Printf("Proceeding with FUSE mount...")
 
 	// Create FUSE filesystem
-	fuseFS := newFuseFS(client, share)
+	cache := newMetaCache(mo.attrTTL, mo.entryTTL)
+	var pages *pageCache
+	if mo.pageCache > 0 {
+		pages = newPageCache(int64(mo.pageCache))
+	}
+	fuseFS := newFuseFS(client, share, mo, cache, pages)
 
-	// Mount options
-	entryTimeout := 1 * time.Second
-	attrTimeout := 1 * time.Second
+	// Mount options; the Watch stream invalidates kernel caches, so long timeouts are safe
+	entryTimeout := mo.entryTTL
+	attrTimeout := mo.attrTTL
 	opts := &fs.Options{
 		MountOptions: fuse.MountOptions{
 			Debug: true, // Enable debug logging
-			// Disable ReadDirPlus to force use of ReadDir
-			DisableReadDirPlus: true,
 		},
 		EntryTimeout: &entryTimeout,
 		AttrTimeout:  &attrTimeout,
 	}
+	if mo.readOnly {
+		// Let the kernel reject writes before they reach us
+		opts.MountOptions.Options = append(opts.MountOptions.Options, "ro")
+	}
 
 	// Mount the filesystem
 	server, err := fs.Mount(mountpoint, fuseFS, opts)
@@ -64,6 +72,14 @@ func mountRemote(share, mountpoint, addr string, readOnly bool) error {
 
 	log.Printf("FUSE filesystem mounted at %s (share=%s, server=%s)", mountpoint, share, addr)
 
+	// Keep caches coherent with changes made on the server side
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go fuseFS.watchChanges(watchCtx)
+
+	// Anything may have changed while the server was unreachable
+	client.OnResync(fuseFS.invalidateAll)
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
@@ -4,7 +4,7 @@ package main
 
 import "fmt"
 
-func mountRemote(share, mountpoint, addr string, readOnly bool) error {
+func mountRemote(share, mountpoint, addr string, opts mountOptions) error {
     return fmt.Errorf("FUSE mount only supported on linux builds")
 }
 
@@ -0,0 +1,45 @@
+//go:build linux
+
+package main
+
+import (
+	"sync"
+	"syscall"
+)
+
+// minNodeSweep is the size up to which the node index is not swept.
+const minNodeSweep = 1024
+
+// nodeIndex finds the node of a file by its inode number. go-fuse only
+// merges a looked-up node with an existing one after Lookup returns, so
+// without this a lookup could not tell that a file it finds under a new name
+// already has a node, possibly at a stale path.
+type nodeIndex struct {
+	mu      sync.Mutex
+	nodes   map[uint64]*fuseFS
+	sweepAt int // Drop forgotten nodes once the index grows to this size
+}
+
+func newNodeIndex() *nodeIndex {
+	return &nodeIndex{nodes: make(map[uint64]*fuseFS), sweepAt: minNodeSweep}
+}
+
+// get returns the node for ino if the kernel still knows it and it has the
+// file type in mode; otherwise it stores and returns fresh.
+func (x *nodeIndex) get(ino uint64, mode uint32, fresh *fuseFS) *fuseFS {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if node, ok := x.nodes[ino]; ok && !node.Forgotten() && node.StableAttr().Mode == mode&syscall.S_IFMT {
+		return node
+	}
+	if len(x.nodes) >= x.sweepAt {
+		for id, node := range x.nodes {
+			if node.Forgotten() {
+				delete(x.nodes, id)
+			}
+		}
+		x.sweepAt = max(minNodeSweep, 2*len(x.nodes))
+	}
+	x.nodes[ino] = fresh
+	return fresh
+}
@@ -0,0 +1,153 @@
+//go:build linux
+
+package main
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// pageSize is the unit of caching and read-ahead; it matches the largest
+// read the kernel sends.
+const pageSize = 128 << 10
+
+// pageVersion identifies one version of a file's contents. A changed mtime
+// yields new keys, so pages of an older version are never served even if an
+// invalidation was missed; they age out of the LRU.
+type pageVersion struct {
+	id    uint64 // Inode number (server file ID)
+	mtime int64  // Nanoseconds since the epoch
+}
+
+type pageKey struct {
+	pageVersion
+	index int64 // Offset / pageSize
+}
+
+type page struct {
+	key  pageKey
+	data []byte // Shorter than pageSize only for the last page of the file
+}
+
+// pageCache is a bounded LRU of file pages shared by all open files of the
+// mount. Concurrent loads of the same page are collapsed into one.
+type pageCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	bytes   int64
+	lru     *list.List // Of *page, most recently used first
+	pages   map[pageKey]*list.Element
+	loading map[pageKey]chan struct{}
+	// Invalidations bump the file's epoch, or the generation for all files,
+	// so loads in flight across them are not stored.
+	epochs     map[uint64]uint64
+	generation uint64
+}
+
+// maxTrackedEpochs bounds the per-file epochs; beyond it they are dropped
+// and the generation is bumped instead.
+const maxTrackedEpochs = 4096
+
+// loadStamp records the invalidation state a load started under.
+type loadStamp struct {
+	generation, epoch uint64
+}
+
+func newPageCache(maxBytes int64) *pageCache {
+	return &pageCache{
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		pages:    make(map[pageKey]*list.Element),
+		loading:  make(map[pageKey]chan struct{}),
+		epochs:   make(map[uint64]uint64),
+	}
+}
+
+// load returns the page for key. On a miss it calls fetch, unless another
+// goroutine is already loading the page, in which case it waits for that.
+func (c *pageCache) load(ctx context.Context, key pageKey, fetch func() ([]byte, error)) ([]byte, error) {
+	for {
+		c.mu.Lock()
+		if el, ok := c.pages[key]; ok {
+			c.lru.MoveToFront(el)
+			data := el.Value.(*page).data
+			c.mu.Unlock()
+			return data, nil
+		}
+		wait, busy := c.loading[key]
+		if !busy {
+			done := make(chan struct{})
+			c.loading[key] = done
+			stamp := c.stampLocked(key.id)
+			c.mu.Unlock()
+
+			data, err := fetch()
+
+			c.mu.Lock()
+			delete(c.loading, key)
+			close(done)
+			if err == nil && stamp == c.stampLocked(key.id) {
+				c.putLocked(key, data)
+			}
+			c.mu.Unlock()
+			return data, err
+		}
+		c.mu.Unlock()
+
+		// Retry after the other load; if it failed, this caller fetches.
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *pageCache) stampLocked(id uint64) loadStamp {
+	return loadStamp{generation: c.generation, epoch: c.epochs[id]}
+}
+
+func (c *pageCache) putLocked(key pageKey, data []byte) {
+	if int64(len(data)) > c.maxBytes {
+		return
+	}
+	c.pages[key] = c.lru.PushFront(&page{key: key, data: data})
+	c.bytes += int64(len(data))
+	for c.bytes > c.maxBytes {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+func (c *pageCache) removeLocked(el *list.Element) {
+	p := c.lru.Remove(el).(*page)
+	delete(c.pages, p.key)
+	c.bytes -= int64(len(p.data))
+}
+
+// invalidate drops every cached page of the file, whatever its version.
+func (c *pageCache) invalidate(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.epochs) >= maxTrackedEpochs {
+		clear(c.epochs)
+		c.generation++
+	}
+	c.epochs[id]++
+	for key, el := range c.pages {
+		if key.id == id {
+			c.removeLocked(el)
+		}
+	}
+}
+
+// clear drops all pages.
+func (c *pageCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.lru.Init()
+	c.pages = make(map[pageKey]*list.Element)
+	c.bytes = 0
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// loadDuring loads key and runs during while the fetch is in flight, then
+// reports whether the page was stored.
+func loadDuring(t *testing.T, c *pageCache, key pageKey, during func()) bool {
+	t.Helper()
+	_, err := c.load(context.Background(), key, func() ([]byte, error) {
+		during()
+		return []byte("data"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, stored := c.pages[key]
+	return stored
+}
+
+func TestPageCacheInvalidationScope(t *testing.T) {
+	a := pageKey{pageVersion{id: 1, mtime: 10}, 0}
+	tests := []struct {
+		name   string
+		during func(c *pageCache)
+		stored bool
+	}{
+		{"no invalidation", func(c *pageCache) {}, true},
+		{"other file invalidated", func(c *pageCache) { c.invalidate(2) }, true},
+		{"same file invalidated", func(c *pageCache) { c.invalidate(1) }, false},
+		{"everything cleared", func(c *pageCache) { c.clear() }, false},
+		{"epochs overflow", func(c *pageCache) {
+			for id := uint64(100); id < 100+maxTrackedEpochs+1; id++ {
+				c.invalidate(id)
+			}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newPageCache(1 << 20)
+			if got := loadDuring(t, c, a, func() { tt.during(c) }); got != tt.stored {
+				t.Errorf("stored = %v, want %v", got, tt.stored)
+			}
+		})
+	}
+}
+
+func TestPageCacheWritesKeepOtherFilesCached(t *testing.T) {
+	c := newPageCache(1 << 20)
+	a := pageKey{pageVersion{id: 1, mtime: 10}, 0}
+	b := pageKey{pageVersion{id: 2, mtime: 10}, 0}
+	loadDuring(t, c, a, func() {})
+	loadDuring(t, c, b, func() {})
+
+	c.invalidate(2)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pages[a]; !ok {
+		t.Error("page of file 1 dropped by invalidating file 2")
+	}
+	if _, ok := c.pages[b]; ok {
+		t.Error("page of file 2 survived its invalidation")
+	}
+}
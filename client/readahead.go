@@ -0,0 +1,152 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+)
+
+// readAhead serves the reads of one open file through the page cache. Once
+// it sees sequential access it prefetches up to window bytes past the last
+// read in the background, streaming the range if streamChunk is set.
+type readAhead struct {
+	client      *grpcClient
+	file        *remoteFile
+	pages       *pageCache
+	window      int64 // 0 disables prefetching
+	streamChunk int32
+
+	ctx    context.Context // Cancelled when the file is released
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	next       int64 // Offset following the previous read
+	hits       int   // Consecutive sequential reads
+	prefetched int64 // End of the range prefetched so far
+	running    bool  // A prefetch is in progress
+}
+
+func newReadAhead(client *grpcClient, file *remoteFile, pages *pageCache, window int64, streamChunk int32) *readAhead {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &readAhead{
+		client:      client,
+		file:        file,
+		pages:       pages,
+		window:      window,
+		streamChunk: streamChunk,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// read fills dest from the pages of version v starting at off, loading
+// missing pages from the server. A short result means end of file.
+func (r *readAhead) read(ctx context.Context, v pageVersion, dest []byte, off int64) ([]byte, error) {
+	r.observe(v, off, len(dest))
+
+	n := 0
+	for n < len(dest) {
+		pos := off + int64(n)
+		index := pos / pageSize
+		data, err := r.pages.load(ctx, pageKey{v, index}, func() ([]byte, error) {
+			return r.client.Read(ctx, r.file, index*pageSize, pageSize)
+		})
+		if err != nil {
+			return nil, err
+		}
+		start := pos - index*pageSize
+		if start >= int64(len(data)) {
+			break
+		}
+		n += copy(dest[n:], data[start:])
+		if len(data) < pageSize {
+			break
+		}
+	}
+	return dest[:n], nil
+}
+
+// observe tracks the access pattern and starts a prefetch when the reader is
+// sequential and less than half a window of data is prefetched ahead of it.
+func (r *readAhead) observe(v pageVersion, off int64, size int) {
+	if r.window <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off == r.next {
+		r.hits++
+	} else {
+		r.hits = 0
+		r.prefetched = 0
+	}
+	r.next = off + int64(size)
+	if r.hits < seqThreshold || r.running {
+		return
+	}
+	start := max(r.prefetched, r.next) / pageSize * pageSize
+	end := r.next + r.window
+	if end-start < r.window/2 {
+		return
+	}
+	r.running = true
+	r.prefetched = end
+	go r.prefetch(v, start, end)
+}
+
+// prefetch loads the pages covering [start, end) into the page cache.
+func (r *readAhead) prefetch(v pageVersion, start, end int64) {
+	defer func() {
+		r.mu.Lock()
+		r.running = false
+		r.mu.Unlock()
+	}()
+
+	first := start / pageSize
+	last := (end + pageSize - 1) / pageSize
+
+	var stream *readStream
+	if r.streamChunk > 0 {
+		ctx, cancel := context.WithCancel(r.ctx)
+		defer cancel()
+		recv, err := r.client.ReadStream(ctx, r.file, first*pageSize, (last-first)*pageSize, r.streamChunk)
+		if err != nil {
+			log.Printf("ReadAhead: stream at offset %d failed: %v", first*pageSize, err)
+		} else {
+			stream = &readStream{cancel: cancel, recv: recv, pos: first * pageSize}
+		}
+	}
+
+	for index := first; index < last; index++ {
+		data, err := r.pages.load(r.ctx, pageKey{v, index}, func() ([]byte, error) {
+			if stream == nil {
+				return r.client.Read(r.ctx, r.file, index*pageSize, pageSize)
+			}
+			// fill skips stream data of pages that were already cached.
+			buf := make([]byte, pageSize)
+			n, err := stream.fill(buf, index*pageSize)
+			if err == io.EOF {
+				err = nil
+			}
+			return buf[:n], err
+		})
+		if err != nil {
+			if r.ctx.Err() == nil {
+				log.Printf("ReadAhead: prefetch at offset %d failed: %v", index*pageSize, err)
+			}
+			return
+		}
+		if len(data) < pageSize {
+			return
+		}
+	}
+}
+
+// close stops any prefetch in progress.
+func (r *readAhead) close() {
+	r.cancel()
+}
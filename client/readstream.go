@@ -0,0 +1,196 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// Sequential access detection for streamed reads.
+const (
+	// seqThreshold is the number of back-to-back reads, each starting where
+	// the previous one ended, after which a ReadStream is started.
+	seqThreshold = 2
+	// maxStreamSkip is how far ahead of the stream position a read may start
+	// and still be served from the stream; the gap is discarded.
+	maxStreamSkip = 1 << 20
+)
+
+// seqReader serves sequential reads of one open file from a server-side
+// ReadStream instead of one unary Read per FUSE request. Reads that do not
+// fit the stream close it and are left to the caller.
+//
+// A read takes the stream out of the reader while it waits for data, so mu
+// is never held across a network call. Reads arriving meanwhile are left to
+// the caller too.
+type seqReader struct {
+	client    *grpcClient
+	file      *remoteFile
+	chunkSize int32 // 0 disables streaming
+
+	mu     sync.Mutex
+	next   int64 // Offset following the previous read
+	hits   int   // Consecutive sequential reads
+	stream *readStream
+	busy   bool   // A read has taken the stream
+	epoch  uint64 // Incremented by reset, so a stream taken before is dropped
+}
+
+// readStream is an active ReadStream; buf holds received bytes starting at
+// file offset pos that have not been consumed yet.
+type readStream struct {
+	cancel context.CancelFunc
+	recv   pb.FileSystemService_ReadStreamClient
+	pos    int64
+	buf    []byte
+}
+
+func newSeqReader(client *grpcClient, file *remoteFile, chunkSize int32) *seqReader {
+	return &seqReader{client: client, file: file, chunkSize: chunkSize}
+}
+
+// read fills dest from the stream if off continues a sequential pattern. It
+// reports false if the caller has to issue a regular Read instead.
+func (r *seqReader) read(dest []byte, off int64) ([]byte, bool) {
+	if r.chunkSize <= 0 {
+		return nil, false
+	}
+	s, epoch, ok := r.take(off, len(dest))
+	if !ok {
+		return nil, false
+	}
+	if s == nil {
+		var err error
+		if s, err = r.open(off); err != nil {
+			log.Printf("ReadStream: start at offset %d failed: %v", off, err)
+			r.giveBack(nil, epoch)
+			return nil, false
+		}
+	}
+
+	n, err := s.fill(dest, off)
+	if err != nil {
+		s.cancel()
+		r.giveBack(nil, epoch)
+		if err == io.EOF {
+			// Later reads go through Read in case the file grows.
+			return dest[:n], true
+		}
+		log.Printf("ReadStream: %v, falling back to Read", err)
+		return nil, false
+	}
+	r.giveBack(s, epoch)
+	return dest[:n], true
+}
+
+// take records a read of size bytes at off and claims the stream for it.
+// It reports false if the read is not to be streamed; a nil stream with true
+// means one has to be started at off.
+func (r *seqReader) take(off int64, size int) (*readStream, uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off == r.next {
+		r.hits++
+	} else {
+		r.hits = 0
+	}
+	r.next = off + int64(size)
+
+	if r.busy {
+		return nil, 0, false
+	}
+	if s := r.stream; s != nil && (off < s.pos || off-s.pos > maxStreamSkip) {
+		r.closeLocked()
+	}
+	if r.stream == nil && r.hits < seqThreshold {
+		return nil, 0, false
+	}
+	s := r.stream
+	r.stream = nil
+	r.busy = true
+	return s, r.epoch, true
+}
+
+// giveBack ends a read that took the stream and keeps s for the next one,
+// unless the reader was reset in the meantime.
+func (r *seqReader) giveBack(s *readStream, epoch uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.busy = false
+	if s != nil && epoch != r.epoch {
+		s.cancel()
+		return
+	}
+	r.stream = s
+}
+
+// reset drops the stream and any data buffered from it, e.g. after a write
+// through the same handle.
+func (r *seqReader) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked()
+	r.hits = 0
+	r.epoch++
+}
+
+func (r *seqReader) open(off int64) (*readStream, error) {
+	// The stream outlives the FUSE request that started it.
+	ctx, cancel := context.WithCancel(context.Background())
+	recv, err := r.client.ReadStream(ctx, r.file, off, 0, r.chunkSize)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &readStream{cancel: cancel, recv: recv, pos: off}, nil
+}
+
+func (r *seqReader) closeLocked() {
+	if r.stream != nil {
+		r.stream.cancel()
+		r.stream = nil
+	}
+}
+
+// fill copies stream data for [off, off+len(dest)) into dest, discarding
+// anything before off. It returns io.EOF with a short count at end of file.
+func (s *readStream) fill(dest []byte, off int64) (int, error) {
+	n := 0
+	for n < len(dest) {
+		if len(s.buf) == 0 {
+			chunk, err := s.recv.Recv()
+			if err != nil {
+				return n, err
+			}
+			switch result := chunk.Result.(type) {
+			case *pb.ReadChunk_Data:
+				if chunk.Offset != s.pos {
+					return n, errors.New("read stream out of order")
+				}
+				s.buf = result.Data
+			case *pb.ReadChunk_Error:
+				return n, newRemoteError("readstream", result.Error)
+			default:
+				return n, errors.New("unexpected read stream chunk")
+			}
+		}
+		if want := off + int64(n); s.pos < want {
+			skip := min(want-s.pos, int64(len(s.buf)))
+			s.buf = s.buf[skip:]
+			s.pos += skip
+			continue
+		}
+		c := copy(dest[n:], s.buf)
+		s.buf = s.buf[c:]
+		s.pos += int64(c)
+		n += c
+	}
+	return n, nil
+}
@@ -0,0 +1,74 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSeqReaderDoesNotBlockWhileWaiting stalls the stream of one read and
+// checks that other reads of the file and a reset go ahead meanwhile.
+func TestSeqReaderDoesNotBlockWhileWaiting(t *testing.T) {
+	const chunk = 4096
+	srv := newFakeServer()
+	data := make([]byte, 16*chunk)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	srv.addFile("f", data)
+	gate := make(chan struct{})
+	srv.streamGate = gate
+	c := srv.dial(t)
+	file, err := c.Open(context.Background(), "f", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newSeqReader(c, file, chunk)
+
+	// The first read is left to the caller, the second starts the stream
+	if _, ok := r.read(make([]byte, chunk), 0); ok {
+		t.Fatal("first read streamed")
+	}
+	type result struct {
+		data []byte
+		ok   bool
+	}
+	done := make(chan result, 1)
+	go func() {
+		got, ok := r.read(make([]byte, chunk), chunk)
+		done <- result{got, ok}
+	}()
+	for srv.streams.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	returned := make(chan bool, 1)
+	go func() {
+		_, ok := r.read(make([]byte, chunk), 2*chunk)
+		r.reset()
+		returned <- ok
+	}()
+	select {
+	case ok := <-returned:
+		if ok {
+			t.Error("read during a stalled stream was streamed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("read blocked behind the stalled stream")
+	}
+
+	close(gate)
+	res := <-done
+	if !res.ok || !bytes.Equal(res.data, data[chunk:2*chunk]) {
+		t.Fatalf("stalled read: ok %v, %d bytes", res.ok, len(res.data))
+	}
+	// It was reset while the read had it, so the stream is gone
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stream != nil || r.busy {
+		t.Errorf("stream kept after reset: %v, busy %v", r.stream != nil, r.busy)
+	}
+}
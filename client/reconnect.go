@@ -0,0 +1,235 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// reconnectParams controls how gRPC re-dials after the connection drops.
+var reconnectParams = grpc.ConnectParams{
+	Backoff: backoff.Config{
+		BaseDelay:  250 * time.Millisecond,
+		Multiplier: 1.6,
+		Jitter:     0.2,
+		MaxDelay:   15 * time.Second,
+	},
+	MinConnectTimeout: 5 * time.Second,
+}
+
+// resyncTimeout bounds re-opening all handles after a reconnect.
+const resyncTimeout = 30 * time.Second
+
+// reopenMask clears open flags that must not be replayed when a handle is
+// re-opened: the file already exists and its contents must be kept.
+const reopenMask = ^uint32(syscall.O_CREAT | syscall.O_EXCL | syscall.O_TRUNC)
+
+// remoteFile is an open server handle that survives reconnects. Handles die
+// with the server connection, so after a reconnect the file is re-opened by
+// path and flags and id is replaced.
+type remoteFile struct {
+	mu    sync.Mutex
+	id    int32
+	path  string
+	flags int32
+}
+
+func (f *remoteFile) handle() int32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.id
+}
+
+// track registers an open handle for re-opening after reconnects.
+func (c *grpcClient) track(id int32, path string, flags int32) *remoteFile {
+	f := &remoteFile{id: id, path: path, flags: int32(uint32(flags) & reopenMask)}
+	c.filesMu.Lock()
+	c.files[f] = struct{}{}
+	c.filesMu.Unlock()
+	return f
+}
+
+func (c *grpcClient) untrack(f *remoteFile) {
+	c.filesMu.Lock()
+	delete(c.files, f)
+	c.filesMu.Unlock()
+}
+
+// renameOpenFiles re-points open handles at or below oldPath to newPath so
+// they are re-opened under the right name; with exchange the two trees swap.
+func (c *grpcClient) renameOpenFiles(oldPath, newPath string, exchange bool) {
+	c.filesMu.Lock()
+	defer c.filesMu.Unlock()
+	for f := range c.files {
+		f.mu.Lock()
+		if p, ok := rebase(f.path, oldPath, newPath); ok {
+			f.path = p
+		} else if exchange {
+			if p, ok := rebase(f.path, newPath, oldPath); ok {
+				f.path = p
+			}
+		}
+		f.mu.Unlock()
+	}
+}
+
+// rebase moves path from below oldRoot to below newRoot.
+func rebase(path, oldRoot, newRoot string) (string, bool) {
+	if path == oldRoot {
+		return newRoot, true
+	}
+	if strings.HasPrefix(path, oldRoot+"/") {
+		return newRoot + path[len(oldRoot):], true
+	}
+	return "", false
+}
+
+// OnResync registers fn to run after a reconnect, once handles are restored.
+// Events may have been lost while disconnected, so caches must be flushed.
+func (c *grpcClient) OnResync(fn func()) {
+	c.filesMu.Lock()
+	defer c.filesMu.Unlock()
+	c.resyncFns = append(c.resyncFns, fn)
+}
+
+// monitor watches the connection state. gRPC re-dials with backoff on its
+// own; once the connection is ready again after a loss, monitor restores the
+// open handles and reports the resync.
+func (c *grpcClient) monitor(ctx context.Context) {
+	lost := false
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			if lost {
+				log.Printf("Reconnected to server, restoring open handles")
+				c.resync(ctx)
+				lost = false
+			}
+		case connectivity.Idle:
+			// An idle channel only reconnects on demand; keep it warm so
+			// handles are restored before the next FUSE request.
+			if !lost {
+				log.Printf("Connection to server went idle, reconnecting")
+			}
+			lost = true
+			c.conn.Connect()
+		case connectivity.TransientFailure, connectivity.Connecting:
+			if !lost {
+				log.Printf("Connection to server lost (state=%v), reconnecting", state)
+			}
+			lost = true
+		case connectivity.Shutdown:
+			return
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return
+		}
+	}
+}
+
+// resync re-opens every tracked handle. It holds the write lock so RPCs
+// issued meanwhile wait and then use the new handle IDs.
+func (c *grpcClient) resync(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, resyncTimeout)
+	defer cancel()
+
+	c.mu.Lock()
+	c.filesMu.Lock()
+	files := make([]*remoteFile, 0, len(c.files))
+	for f := range c.files {
+		files = append(files, f)
+	}
+	fns := append([]func(){}, c.resyncFns...)
+	c.filesMu.Unlock()
+
+	restored := 0
+	for _, f := range files {
+		if err := c.reopenLocked(ctx, f, true); err != nil {
+			log.Printf("Resync: re-open of %s failed: %v", f.path, err)
+			continue
+		}
+		restored++
+	}
+	c.mu.Unlock()
+
+	log.Printf("Resync: restored %d of %d open handles", restored, len(files))
+	if err := c.negotiate(ctx); err != nil {
+		log.Printf("Resync: capability negotiation failed, keeping max read size %d: %v", c.maxRead.Load(), err)
+	}
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// reopenLocked opens f again by path and swaps in the new handle ID; the
+// caller must hold c.mu. With closeOld the old ID is closed on the server
+// under the same lock, in case it is still valid there: a withHandle retry
+// may have re-opened the file on the new connection before resync ran. After
+// a restart the old ID is unknown and the Close fails harmlessly.
+func (c *grpcClient) reopenLocked(ctx context.Context, f *remoteFile, closeOld bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resp, err := c.client.Open(ctx, &pb.OpenRequest{Path: f.path, Flags: f.flags})
+	if err != nil {
+		return err
+	}
+	switch result := resp.Result.(type) {
+	case *pb.OpenResponse_Handle:
+		old := f.id
+		f.id = result.Handle
+		if closeOld {
+			_, _ = c.client.Close(ctx, &pb.CloseRequest{Handle: old})
+		}
+		return nil
+	case *pb.OpenResponse_Error:
+		return newRemoteError("open", result.Error)
+	default:
+		return errors.New("unexpected open response")
+	}
+}
+
+// withHandle runs op with f's current handle ID. If the server reports that
+// it does not know the handle (it restarted before the monitor noticed, or
+// reaped the handle while idle), the file is re-opened and op retried once.
+// Any other error, EBADF included, is returned as is.
+func (c *grpcClient) withHandle(ctx context.Context, f *remoteFile, op func(client pb.FileSystemServiceClient, id int32) error) error {
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	id := f.handle()
+	err := op(client, id)
+	var remote *remoteError
+	if !errors.As(err, &remote) || !remote.unknownHandle {
+		return err
+	}
+
+	log.Printf("Handle %d is unknown to the server, re-opening", id)
+	c.mu.Lock()
+	var reopenErr error
+	if f.handle() == id {
+		// Not yet replaced by a concurrent retry or a resync. The server
+		// does not hold id, so there is nothing to close.
+		reopenErr = c.reopenLocked(ctx, f, false)
+	}
+	client = c.client
+	c.mu.Unlock()
+	if reopenErr != nil {
+		return err
+	}
+	return op(client, f.handle())
+}
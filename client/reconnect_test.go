@@ -0,0 +1,74 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+func openFake(t *testing.T, srv *fakeServer) (*grpcClient, *remoteFile) {
+	t.Helper()
+	srv.addFile("f", []byte("data"))
+	c := srv.dial(t)
+	file, err := c.Open(context.Background(), "f", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, file
+}
+
+func TestUnknownHandleIsReopened(t *testing.T) {
+	srv := newFakeServer()
+	c, file := openFake(t, srv)
+	old := file.handle()
+
+	// The server forgot the handle, e.g. after reaping it
+	srv.mu.Lock()
+	delete(srv.handles, old)
+	srv.mu.Unlock()
+	data, err := c.Read(context.Background(), file, 0, 4)
+	if err != nil || string(data) != "data" {
+		t.Fatalf("Read after the handle was dropped: %q, %v", data, err)
+	}
+	if file.handle() == old || srv.opens.Load() != 2 {
+		t.Errorf("handle %d after %d opens, want a new one", file.handle(), srv.opens.Load())
+	}
+}
+
+func TestPlainEBADFIsNotRetried(t *testing.T) {
+	srv := newFakeServer()
+	c, file := openFake(t, srv)
+	old := file.handle()
+
+	// What reading a write-only handle yields
+	srv.readErr = &pb.Error{Code: int32(syscall.EBADF), Message: "bad file descriptor"}
+	_, err := c.Read(context.Background(), file, 0, 4)
+	if !errors.Is(toErrno(err), syscall.EBADF) {
+		t.Fatalf("Read: %v, want EBADF", err)
+	}
+	if file.handle() != old || srv.opens.Load() != 1 {
+		t.Errorf("file re-opened on a plain EBADF: handle %d, %d opens", file.handle(), srv.opens.Load())
+	}
+}
+
+func TestResyncClosesReplacedHandles(t *testing.T) {
+	srv := newFakeServer()
+	c, file := openFake(t, srv)
+	old := file.handle()
+
+	// As if the handle had been re-opened on the new connection already
+	c.resync(context.Background())
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if _, ok := srv.handles[old]; ok {
+		t.Error("replaced handle left open on the server")
+	}
+	if _, ok := srv.handles[file.handle()]; !ok || len(srv.handles) != 1 {
+		t.Errorf("server holds %d handles, want only the new one", len(srv.handles))
+	}
+}
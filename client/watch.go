@@ -0,0 +1,254 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// Re-subscription backoff after the Watch stream fails.
+const (
+	watchRetryMin = 250 * time.Millisecond
+	watchRetryMax = 30 * time.Second
+)
+
+// watchMissedHeartbeats is how many heartbeat intervals of silence make the
+// client give up on a Watch stream and resubscribe.
+const watchMissedHeartbeats = 3
+
+// watchChanges subscribes to change events for the whole share and keeps the
+// metadata cache and the kernel's caches in sync until ctx is cancelled.
+// It must be called on the root node after the filesystem is mounted.
+func (f *fuseFS) watchChanges(ctx context.Context) {
+	delay := watchRetryMin
+	resubscribe := false
+	for {
+		err := f.consumeWatch(ctx, func() {
+			if resubscribe {
+				// Events may have been missed while the stream was down.
+				f.invalidateAll()
+			}
+			delay = watchRetryMin
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		resubscribe = true
+		log.Printf("Watch: stream ended, error=%v; retrying in %v", err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, watchRetryMax)
+	}
+}
+
+// consumeWatch runs one Watch stream; subscribed is called once the
+// subscription for the share root has been sent. The stream is abandoned if
+// the server, after acknowledging, stays silent for several heartbeat
+// intervals.
+func (f *fuseFS) consumeWatch(ctx context.Context, subscribed func()) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stream, err := f.client.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.WatchRequest{Path: ".", Recursive: true}); err != nil {
+		return err
+	}
+	subscribed()
+	log.Printf("Watch: subscribed to share changes")
+
+	var watchdog *time.Timer
+	var silence time.Duration
+	defer func() {
+		if watchdog != nil {
+			watchdog.Stop()
+		}
+	}()
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+				return cause
+			}
+			return err
+		}
+		if watchdog != nil {
+			watchdog.Reset(silence)
+		}
+
+		switch c := ev.Control.(type) {
+		case *pb.WatchEvent_Ack:
+			log.Printf("Watch: subscription %d for %q acknowledged", c.Ack.SubscriptionId, c.Ack.Path)
+			if c.Ack.HeartbeatIntervalMs > 0 && watchdog == nil {
+				silence = watchMissedHeartbeats * time.Duration(c.Ack.HeartbeatIntervalMs) * time.Millisecond
+				watchdog = time.AfterFunc(silence, func() {
+					cancel(fmt.Errorf("no message from server for %v", silence))
+				})
+			}
+		case *pb.WatchEvent_Error:
+			perr := c.Error.GetError()
+			if perr == nil {
+				perr = &pb.Error{Message: "unspecified watch error"}
+			}
+			err := newRemoteError("watch", perr)
+			if c.Error.SubscriptionId != 0 {
+				// Our only subscription failed; retry with backoff.
+				return fmt.Errorf("subscription %d for %q: %w", c.Error.SubscriptionId, c.Error.Path, err)
+			}
+			log.Printf("Watch: server watcher error: %v", err)
+		case *pb.WatchEvent_Overflow:
+			log.Printf("Watch: server dropped events below %q; invalidating caches", c.Overflow.Path)
+			f.invalidateAll()
+		case *pb.WatchEvent_Heartbeat:
+		default:
+			f.applyEvent(ev)
+		}
+	}
+}
+
+// applyEvent invalidates cached metadata for the event's path and tells the
+// kernel to drop its dentries, attributes or pages accordingly.
+func (f *fuseFS) applyEvent(ev *pb.WatchEvent) {
+	if ev.Path == "" {
+		return
+	}
+
+	path := filepath.Clean(ev.Path)
+	log.Printf("Watch: event type=%v, path=%s", ev.Type, path)
+
+	switch ev.Type {
+	case pb.WatchEventType_CREATE:
+		f.cache.invalidate(path)
+		f.notifyEntry(path, false)
+	case pb.WatchEventType_RENAME:
+		if ev.OldPath == "" {
+			f.cache.invalidateTree(path)
+			f.notifyEntry(path, true)
+			return
+		}
+		f.moveEntry(filepath.Clean(ev.OldPath), path)
+	case pb.WatchEventType_DELETE:
+		f.cache.invalidateTree(path)
+		f.notifyEntry(path, true)
+	case pb.WatchEventType_MODIFY:
+		f.cache.invalidate(path)
+		if node := f.findInode(path); node != nil {
+			if n, ok := node.Operations().(*fuseFS); ok {
+				n.invalidatePages()
+			}
+			// Drop cached pages and attributes.
+			_ = node.NotifyContent(0, 0)
+		}
+	case pb.WatchEventType_ATTRIB:
+		f.cache.invalidate(path)
+		if node := f.findInode(path); node != nil {
+			// A negative offset only drops attributes.
+			_ = node.NotifyContent(-1, 0)
+		}
+	}
+}
+
+// moveEntry applies a rename done on the server. A known inode is moved to
+// its new name, keeping its kernel caches and open files; only the two
+// dentries are invalidated. Renames this client made itself arrive after
+// go-fuse has already moved the inode and find nothing at oldPath.
+func (f *fuseFS) moveEntry(oldPath, newPath string) {
+	log.Printf("Watch: rename %s -> %s", oldPath, newPath)
+	f.cache.invalidateTree(oldPath)
+	f.cache.invalidateTree(newPath)
+
+	oldParent := f.findInode(parentDir(oldPath))
+	newParent := f.findInode(parentDir(newPath))
+	oldName, newName := filepath.Base(oldPath), filepath.Base(newPath)
+	var moved *fs.Inode
+	if oldParent != nil {
+		moved = oldParent.GetChild(oldName)
+	}
+	if moved == nil {
+		f.notifyEntry(oldPath, true)
+		f.notifyEntry(newPath, false)
+		return
+	}
+
+	f.client.renameOpenFiles(oldPath, newPath, false)
+	if node, ok := moved.Operations().(*fuseFS); ok {
+		node.setPath(newPath)
+	}
+	_ = oldParent.NotifyDelete(oldName, moved)
+	if newParent == nil {
+		// The destination is not known to the kernel; look it up afresh
+		oldParent.RmChild(oldName)
+		return
+	}
+	oldParent.MvChild(oldName, newParent, newName, true)
+	_ = newParent.NotifyEntry(newName)
+}
+
+// notifyEntry invalidates the kernel dentry for path; removed reports that
+// the entry is gone, so inotify watchers on the mount see a delete.
+func (f *fuseFS) notifyEntry(path string, removed bool) {
+	parent := f.findInode(parentDir(path))
+	if parent == nil {
+		return
+	}
+	name := filepath.Base(path)
+	if removed {
+		if child := parent.GetChild(name); child != nil {
+			_ = parent.NotifyDelete(name, child)
+			return
+		}
+	}
+	_ = parent.NotifyEntry(name)
+}
+
+// findInode walks the known inode tree from the root to path; it returns nil
+// if the kernel has not looked the path up.
+func (f *fuseFS) findInode(path string) *fs.Inode {
+	node := f.Root()
+	if path == "." || path == "" {
+		return node
+	}
+	for _, name := range strings.Split(path, "/") {
+		node = node.GetChild(name)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// invalidateAll flushes the metadata cache and every kernel entry, attribute
+// and page cached for known inodes. Used when change events may have been lost.
+func (f *fuseFS) invalidateAll() {
+	f.cache.clear()
+	if f.pages != nil {
+		f.pages.clear()
+	}
+	invalidateTree(f.Root())
+}
+
+func invalidateTree(node *fs.Inode) {
+	if node.IsDir() {
+		_ = node.NotifyContent(-1, 0)
+	} else {
+		_ = node.NotifyContent(0, 0)
+	}
+	for name, child := range node.Children() {
+		_ = node.NotifyEntry(name)
+		invalidateTree(child)
+	}
+}
@@ -21,6 +21,52 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type WatchOperation int32
+
+const (
+	WatchOperation_SUBSCRIBE   WatchOperation = 0
+	WatchOperation_UNSUBSCRIBE WatchOperation = 1
+)
+
+// Enum value maps for WatchOperation.
+var (
+	WatchOperation_name = map[int32]string{
+		0: "SUBSCRIBE",
+		1: "UNSUBSCRIBE",
+	}
+	WatchOperation_value = map[string]int32{
+		"SUBSCRIBE":   0,
+		"UNSUBSCRIBE": 1,
+	}
+)
+
+func (x WatchOperation) Enum() *WatchOperation {
+	p := new(WatchOperation)
+	*p = x
+	return p
+}
+
+func (x WatchOperation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchOperation) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_fsdriver_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchOperation) Type() protoreflect.EnumType {
+	return &file_proto_fsdriver_proto_enumTypes[0]
+}
+
+func (x WatchOperation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchOperation.Descriptor instead.
+func (WatchOperation) EnumDescriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{0}
+}
+
 type WatchEventType int32
 
 const (
@@ -28,7 +74,7 @@ const (
 	WatchEventType_CREATE  WatchEventType = 1
 	WatchEventType_DELETE  WatchEventType = 2
 	WatchEventType_MODIFY  WatchEventType = 3
-	WatchEventType_RENAME  WatchEventType = 4
+	WatchEventType_RENAME  WatchEventType = 4 // Moved from old_path to path within the share
 	WatchEventType_ATTRIB  WatchEventType = 5 // Attribute change
 )
 
@@ -63,11 +109,11 @@ func (x WatchEventType) String() string {
 }
 
 func (WatchEventType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_fsdriver_proto_enumTypes[0].Descriptor()
+	return file_proto_fsdriver_proto_enumTypes[1].Descriptor()
 }
 
 func (WatchEventType) Type() protoreflect.EnumType {
-	return &file_proto_fsdriver_proto_enumTypes[0]
+	return &file_proto_fsdriver_proto_enumTypes[1]
 }
 
 func (x WatchEventType) Number() protoreflect.EnumNumber {
@@ -76,29 +122,129 @@ func (x WatchEventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WatchEventType.Descriptor instead.
 func (WatchEventType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{0}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{1}
 }
 
-// File attributes (POSIX-like)
-type FileInfo struct {
+// Capabilities request/response
+type CapabilitiesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	IsDir         bool                   `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
-	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"` // Unix timestamp
-	AccessTime    int64                  `protobuf:"varint,5,opt,name=access_time,json=accessTime,proto3" json:"access_time,omitempty"`
-	ChangeTime    int64                  `protobuf:"varint,6,opt,name=change_time,json=changeTime,proto3" json:"change_time,omitempty"`
-	Mode          uint32                 `protobuf:"varint,7,opt,name=mode,proto3" json:"mode,omitempty"` // POSIX permissions
-	Uid           uint32                 `protobuf:"varint,8,opt,name=uid,proto3" json:"uid,omitempty"`
-	Gid           uint32                 `protobuf:"varint,9,opt,name=gid,proto3" json:"gid,omitempty"`
-	IsSymlink     bool                   `protobuf:"varint,10,opt,name=is_symlink,json=isSymlink,proto3" json:"is_symlink,omitempty"`
+	MaxReadSize   int32                  `protobuf:"varint,1,opt,name=max_read_size,json=maxReadSize,proto3" json:"max_read_size,omitempty"` // Largest read the client wants to issue; 0 = no preference
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CapabilitiesRequest) Reset() {
+	*x = CapabilitiesRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilitiesRequest) ProtoMessage() {}
+
+func (x *CapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*CapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CapabilitiesRequest) GetMaxReadSize() int32 {
+	if x != nil {
+		return x.MaxReadSize
+	}
+	return 0
+}
+
+type CapabilitiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Largest Read size and ReadStream chunk the server serves. Larger Reads
+	// return short; the client must continue at the returned length.
+	MaxReadSize   int32 `protobuf:"varint,1,opt,name=max_read_size,json=maxReadSize,proto3" json:"max_read_size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *CapabilitiesResponse) Reset() {
+	*x = CapabilitiesResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilitiesResponse) ProtoMessage() {}
+
+func (x *CapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CapabilitiesResponse) GetMaxReadSize() int32 {
+	if x != nil {
+		return x.MaxReadSize
+	}
+	return 0
+}
+
+// File attributes (POSIX-like)
+type FileInfo struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Name       string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	IsDir      bool                   `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Size       int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime    int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"` // Unix timestamp
+	AccessTime int64                  `protobuf:"varint,5,opt,name=access_time,json=accessTime,proto3" json:"access_time,omitempty"`
+	ChangeTime int64                  `protobuf:"varint,6,opt,name=change_time,json=changeTime,proto3" json:"change_time,omitempty"`
+	Mode       uint32                 `protobuf:"varint,7,opt,name=mode,proto3" json:"mode,omitempty"` // POSIX permissions
+	Uid        uint32                 `protobuf:"varint,8,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid        uint32                 `protobuf:"varint,9,opt,name=gid,proto3" json:"gid,omitempty"`
+	IsSymlink  bool                   `protobuf:"varint,10,opt,name=is_symlink,json=isSymlink,proto3" json:"is_symlink,omitempty"`
+	Dev        uint64                 `protobuf:"varint,11,opt,name=dev,proto3" json:"dev,omitempty"`     // Device / volume serial number
+	Ino        uint64                 `protobuf:"varint,12,opt,name=ino,proto3" json:"ino,omitempty"`     // Stable file ID on dev (inode / NTFS file index), 0 if unknown
+	Nlink      uint32                 `protobuf:"varint,13,opt,name=nlink,proto3" json:"nlink,omitempty"` // Number of hard links
+	// Sub-second parts of mod_time, access_time and change_time (0-999999999)
+	ModTimeNsec    uint32 `protobuf:"varint,14,opt,name=mod_time_nsec,json=modTimeNsec,proto3" json:"mod_time_nsec,omitempty"`
+	AccessTimeNsec uint32 `protobuf:"varint,15,opt,name=access_time_nsec,json=accessTimeNsec,proto3" json:"access_time_nsec,omitempty"`
+	ChangeTimeNsec uint32 `protobuf:"varint,16,opt,name=change_time_nsec,json=changeTimeNsec,proto3" json:"change_time_nsec,omitempty"`
+	BirthTime      int64  `protobuf:"varint,17,opt,name=birth_time,json=birthTime,proto3" json:"birth_time,omitempty"` // Creation time as Unix timestamp; 0 if unknown
+	BirthTimeNsec  uint32 `protobuf:"varint,18,opt,name=birth_time_nsec,json=birthTimeNsec,proto3" json:"birth_time_nsec,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
 func (x *FileInfo) Reset() {
 	*x = FileInfo{}
-	mi := &file_proto_fsdriver_proto_msgTypes[0]
+	mi := &file_proto_fsdriver_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -110,7 +256,7 @@ func (x *FileInfo) String() string {
 func (*FileInfo) ProtoMessage() {}
 
 func (x *FileInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[0]
+	mi := &file_proto_fsdriver_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -123,7 +269,7 @@ func (x *FileInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
 func (*FileInfo) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{0}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *FileInfo) GetName() string {
@@ -196,19 +342,80 @@ func (x *FileInfo) GetIsSymlink() bool {
 	return false
 }
 
+func (x *FileInfo) GetDev() uint64 {
+	if x != nil {
+		return x.Dev
+	}
+	return 0
+}
+
+func (x *FileInfo) GetIno() uint64 {
+	if x != nil {
+		return x.Ino
+	}
+	return 0
+}
+
+func (x *FileInfo) GetNlink() uint32 {
+	if x != nil {
+		return x.Nlink
+	}
+	return 0
+}
+
+func (x *FileInfo) GetModTimeNsec() uint32 {
+	if x != nil {
+		return x.ModTimeNsec
+	}
+	return 0
+}
+
+func (x *FileInfo) GetAccessTimeNsec() uint32 {
+	if x != nil {
+		return x.AccessTimeNsec
+	}
+	return 0
+}
+
+func (x *FileInfo) GetChangeTimeNsec() uint32 {
+	if x != nil {
+		return x.ChangeTimeNsec
+	}
+	return 0
+}
+
+func (x *FileInfo) GetBirthTime() int64 {
+	if x != nil {
+		return x.BirthTime
+	}
+	return 0
+}
+
+func (x *FileInfo) GetBirthTimeNsec() uint32 {
+	if x != nil {
+		return x.BirthTimeNsec
+	}
+	return 0
+}
+
 // Error details
 type Error struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`                            // POSIX errno
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                       // Human readable
-	Win32Code     int32                  `protobuf:"varint,3,opt,name=win32_code,json=win32Code,proto3" json:"win32_code,omitempty"` // Original Windows error code
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Code      int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`                            // POSIX errno
+	Message   string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                       // Human readable
+	Win32Code int32                  `protobuf:"varint,3,opt,name=win32_code,json=win32Code,proto3" json:"win32_code,omitempty"` // Original Windows error code
+	// The request named a handle ID the server does not hold for this
+	// connection: closed, reaped when idle, or opened before a restart. Only
+	// then may a client re-open the file and retry; a plain EBADF, e.g. from
+	// reading a write-only handle, is final.
+	UnknownHandle bool `protobuf:"varint,4,opt,name=unknown_handle,json=unknownHandle,proto3" json:"unknown_handle,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Error) Reset() {
 	*x = Error{}
-	mi := &file_proto_fsdriver_proto_msgTypes[1]
+	mi := &file_proto_fsdriver_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -220,7 +427,7 @@ func (x *Error) String() string {
 func (*Error) ProtoMessage() {}
 
 func (x *Error) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[1]
+	mi := &file_proto_fsdriver_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -233,7 +440,7 @@ func (x *Error) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Error.ProtoReflect.Descriptor instead.
 func (*Error) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{1}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Error) GetCode() int32 {
@@ -257,6 +464,13 @@ func (x *Error) GetWin32Code() int32 {
 	return 0
 }
 
+func (x *Error) GetUnknownHandle() bool {
+	if x != nil {
+		return x.UnknownHandle
+	}
+	return false
+}
+
 // Stat request/response
 type StatRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -267,7 +481,7 @@ type StatRequest struct {
 
 func (x *StatRequest) Reset() {
 	*x = StatRequest{}
-	mi := &file_proto_fsdriver_proto_msgTypes[2]
+	mi := &file_proto_fsdriver_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -279,7 +493,7 @@ func (x *StatRequest) String() string {
 func (*StatRequest) ProtoMessage() {}
 
 func (x *StatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[2]
+	mi := &file_proto_fsdriver_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -292,7 +506,7 @@ func (x *StatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
 func (*StatRequest) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{2}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *StatRequest) GetPath() string {
@@ -315,7 +529,7 @@ type StatResponse struct {
 
 func (x *StatResponse) Reset() {
 	*x = StatResponse{}
-	mi := &file_proto_fsdriver_proto_msgTypes[3]
+	mi := &file_proto_fsdriver_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -327,7 +541,7 @@ func (x *StatResponse) String() string {
 func (*StatResponse) ProtoMessage() {}
 
 func (x *StatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[3]
+	mi := &file_proto_fsdriver_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +554,7 @@ func (x *StatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
 func (*StatResponse) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{3}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *StatResponse) GetResult() isStatResponse_Result {
@@ -386,17 +600,25 @@ func (*StatResponse_Error) isStatResponse_Result() {}
 
 // ReadDir request/response
 type ReadDirRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`      // Relative to share root
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // For pagination
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`   // Max entries to return (0 = all)
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
+	// Replaced by cursor. Kept so that a request from an older client paging
+	// by offset is rejected with EINVAL instead of restarting the listing.
+	//
+	// Deprecated: Marked as deprecated in proto/fsdriver.proto.
+	Offset int32  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit  int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`  // Max entries per page (0 = server default)
+	Cursor string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page; empty starts a listing
+	// Ends the listing of cursor without reading further, for a client that
+	// stops early. The server closes the cursor and returns no entries.
+	Release       bool `protobuf:"varint,5,opt,name=release,proto3" json:"release,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadDirRequest) Reset() {
 	*x = ReadDirRequest{}
-	mi := &file_proto_fsdriver_proto_msgTypes[4]
+	mi := &file_proto_fsdriver_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -408,7 +630,7 @@ func (x *ReadDirRequest) String() string {
 func (*ReadDirRequest) ProtoMessage() {}
 
 func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[4]
+	mi := &file_proto_fsdriver_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -421,7 +643,7 @@ func (x *ReadDirRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadDirRequest.ProtoReflect.Descriptor instead.
 func (*ReadDirRequest) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{4}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ReadDirRequest) GetPath() string {
@@ -431,6 +653,7 @@ func (x *ReadDirRequest) GetPath() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in proto/fsdriver.proto.
 func (x *ReadDirRequest) GetOffset() int32 {
 	if x != nil {
 		return x.Offset
@@ -445,18 +668,33 @@ func (x *ReadDirRequest) GetLimit() int32 {
 	return 0
 }
 
+func (x *ReadDirRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ReadDirRequest) GetRelease() bool {
+	if x != nil {
+		return x.Release
+	}
+	return false
+}
+
 type ReadDirResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Entries       []*FileInfo            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	HasMore       bool                   `protobuf:"varint,2,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
 	Error         *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Set when has_more; expires when unused for a while
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadDirResponse) Reset() {
 	*x = ReadDirResponse{}
-	mi := &file_proto_fsdriver_proto_msgTypes[5]
+	mi := &file_proto_fsdriver_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -468,7 +706,7 @@ func (x *ReadDirResponse) String() string {
 func (*ReadDirResponse) ProtoMessage() {}
 
 func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[5]
+	mi := &file_proto_fsdriver_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -481,7 +719,7 @@ func (x *ReadDirResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadDirResponse.ProtoReflect.Descriptor instead.
 func (*ReadDirResponse) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{5}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ReadDirResponse) GetEntries() []*FileInfo {
@@ -505,18 +743,25 @@ func (x *ReadDirResponse) GetError() *Error {
 	return nil
 }
 
+func (x *ReadDirResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 // Open request/response
 type OpenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`    // Relative to share root
-	Flags         int32                  `protobuf:"varint,2,opt,name=flags,proto3" json:"flags,omitempty"` // O_RDONLY, O_RDWR, etc.
+	Flags         int32                  `protobuf:"varint,2,opt,name=flags,proto3" json:"flags,omitempty"` // Linux open(2) flags: O_RDONLY, O_RDWR, O_CREAT, etc.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OpenRequest) Reset() {
 	*x = OpenRequest{}
-	mi := &file_proto_fsdriver_proto_msgTypes[6]
+	mi := &file_proto_fsdriver_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -528,7 +773,7 @@ func (x *OpenRequest) String() string {
 func (*OpenRequest) ProtoMessage() {}
 
 func (x *OpenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[6]
+	mi := &file_proto_fsdriver_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -541,7 +786,7 @@ func (x *OpenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OpenRequest.ProtoReflect.Descriptor instead.
 func (*OpenRequest) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{6}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *OpenRequest) GetPath() string {
@@ -571,7 +816,7 @@ type OpenResponse struct {
 
 func (x *OpenResponse) Reset() {
 	*x = OpenResponse{}
-	mi := &file_proto_fsdriver_proto_msgTypes[7]
+	mi := &file_proto_fsdriver_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -583,7 +828,7 @@ func (x *OpenResponse) String() string {
 func (*OpenResponse) ProtoMessage() {}
 
 func (x *OpenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[7]
+	mi := &file_proto_fsdriver_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -596,7 +841,7 @@ func (x *OpenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OpenResponse.ProtoReflect.Descriptor instead.
 func (*OpenResponse) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{7}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *OpenResponse) GetResult() isOpenResponse_Result {
@@ -652,7 +897,7 @@ type ReadRequest struct {
 
 func (x *ReadRequest) Reset() {
 	*x = ReadRequest{}
-	mi := &file_proto_fsdriver_proto_msgTypes[8]
+	mi := &file_proto_fsdriver_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -664,7 +909,7 @@ func (x *ReadRequest) String() string {
 func (*ReadRequest) ProtoMessage() {}
 
 func (x *ReadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[8]
+	mi := &file_proto_fsdriver_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -677,7 +922,7 @@ func (x *ReadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadRequest.ProtoReflect.Descriptor instead.
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{8}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ReadRequest) GetHandle() int32 {
@@ -714,7 +959,7 @@ type ReadResponse struct {
 
 func (x *ReadResponse) Reset() {
 	*x = ReadResponse{}
-	mi := &file_proto_fsdriver_proto_msgTypes[9]
+	mi := &file_proto_fsdriver_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -726,7 +971,7 @@ func (x *ReadResponse) String() string {
 func (*ReadResponse) ProtoMessage() {}
 
 func (x *ReadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[9]
+	mi := &file_proto_fsdriver_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,7 +984,7 @@ func (x *ReadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadResponse.ProtoReflect.Descriptor instead.
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{9}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ReadResponse) GetResult() isReadResponse_Result {
@@ -783,29 +1028,34 @@ func (*ReadResponse_Data) isReadResponse_Result() {}
 
 func (*ReadResponse_Error) isReadResponse_Result() {}
 
-// Close request/response
-type CloseRequest struct {
+// ReadStream request/chunk. The server sends chunks in file order until the
+// range is exhausted or EOF is reached; HTTP/2 flow control keeps it from
+// running ahead of the client.
+type ReadStreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Handle        int32                  `protobuf:"varint,1,opt,name=handle,proto3" json:"handle,omitempty"` // From OpenResponse
+	Handle        int32                  `protobuf:"varint,1,opt,name=handle,proto3" json:"handle,omitempty"`                        // From OpenResponse
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`                        // Start offset
+	Length        int64                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`                        // Bytes to stream; 0 = until EOF
+	ChunkSize     int32                  `protobuf:"varint,4,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"` // Preferred chunk size; 0 = server default
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CloseRequest) Reset() {
-	*x = CloseRequest{}
-	mi := &file_proto_fsdriver_proto_msgTypes[10]
+func (x *ReadStreamRequest) Reset() {
+	*x = ReadStreamRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CloseRequest) String() string {
+func (x *ReadStreamRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CloseRequest) ProtoMessage() {}
+func (*ReadStreamRequest) ProtoMessage() {}
 
-func (x *CloseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[10]
+func (x *ReadStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -816,31 +1066,187 @@ func (x *CloseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CloseRequest.ProtoReflect.Descriptor instead.
-func (*CloseRequest) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ReadStreamRequest.ProtoReflect.Descriptor instead.
+func (*ReadStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *CloseRequest) GetHandle() int32 {
+func (x *ReadStreamRequest) GetHandle() int32 {
 	if x != nil {
 		return x.Handle
 	}
 	return 0
 }
 
-type CloseResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ReadStreamRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
 }
 
-func (x *CloseResponse) Reset() {
-	*x = CloseResponse{}
-	mi := &file_proto_fsdriver_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
+func (x *ReadStreamRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *ReadStreamRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+type ReadChunk struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Offset int64                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"` // File offset of data
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ReadChunk_Data
+	//	*ReadChunk_Error
+	Result        isReadChunk_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadChunk) Reset() {
+	*x = ReadChunk{}
+	mi := &file_proto_fsdriver_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadChunk) ProtoMessage() {}
+
+func (x *ReadChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadChunk.ProtoReflect.Descriptor instead.
+func (*ReadChunk) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReadChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ReadChunk) GetResult() isReadChunk_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ReadChunk) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Result.(*ReadChunk_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+func (x *ReadChunk) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*ReadChunk_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isReadChunk_Result interface {
+	isReadChunk_Result()
+}
+
+type ReadChunk_Data struct {
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3,oneof"` // File data, never empty
+}
+
+type ReadChunk_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"` // Terminates the stream
+}
+
+func (*ReadChunk_Data) isReadChunk_Result() {}
+
+func (*ReadChunk_Error) isReadChunk_Result() {}
+
+// Close request/response
+type CloseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        int32                  `protobuf:"varint,1,opt,name=handle,proto3" json:"handle,omitempty"` // From OpenResponse
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseRequest) Reset() {
+	*x = CloseRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseRequest) ProtoMessage() {}
+
+func (x *CloseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseRequest.ProtoReflect.Descriptor instead.
+func (*CloseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CloseRequest) GetHandle() int32 {
+	if x != nil {
+		return x.Handle
+	}
+	return 0
+}
+
+type CloseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseResponse) Reset() {
+	*x = CloseResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
 func (x *CloseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
@@ -849,7 +1255,7 @@ func (x *CloseResponse) String() string {
 func (*CloseResponse) ProtoMessage() {}
 
 func (x *CloseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[11]
+	mi := &file_proto_fsdriver_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -862,7 +1268,7 @@ func (x *CloseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CloseResponse.ProtoReflect.Descriptor instead.
 func (*CloseResponse) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{11}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *CloseResponse) GetError() *Error {
@@ -872,18 +1278,1452 @@ func (x *CloseResponse) GetError() *Error {
 	return nil
 }
 
-// Watch request (client to server)
+// Create request/response
+type CreateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`    // Relative to share root
+	Flags         int32                  `protobuf:"varint,2,opt,name=flags,proto3" json:"flags,omitempty"` // Linux open(2) flags; O_CREAT is implied
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`   // POSIX permissions for the new file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetFlags() int32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+func (x *CreateRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+type CreateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        int32                  `protobuf:"varint,1,opt,name=handle,proto3" json:"handle,omitempty"` // File handle for subsequent operations
+	Info          *FileInfo              `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`      // Attributes of the created file
+	Error         *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResponse) Reset() {
+	*x = CreateResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponse) ProtoMessage() {}
+
+func (x *CreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponse.ProtoReflect.Descriptor instead.
+func (*CreateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CreateResponse) GetHandle() int32 {
+	if x != nil {
+		return x.Handle
+	}
+	return 0
+}
+
+func (x *CreateResponse) GetInfo() *FileInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *CreateResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Write request/response
+type WriteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Handle        int32                  `protobuf:"varint,1,opt,name=handle,proto3" json:"handle,omitempty"` // From OpenResponse or CreateResponse
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // Write offset (ignored for O_APPEND handles)
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`      // Data to write
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteRequest) Reset() {
+	*x = WriteRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteRequest) ProtoMessage() {}
+
+func (x *WriteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteRequest.ProtoReflect.Descriptor instead.
+func (*WriteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WriteRequest) GetHandle() int32 {
+	if x != nil {
+		return x.Handle
+	}
+	return 0
+}
+
+func (x *WriteRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *WriteRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type WriteResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*WriteResponse_Written
+	//	*WriteResponse_Error
+	Result        isWriteResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteResponse) Reset() {
+	*x = WriteResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteResponse) ProtoMessage() {}
+
+func (x *WriteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteResponse.ProtoReflect.Descriptor instead.
+func (*WriteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WriteResponse) GetResult() isWriteResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *WriteResponse) GetWritten() int32 {
+	if x != nil {
+		if x, ok := x.Result.(*WriteResponse_Written); ok {
+			return x.Written
+		}
+	}
+	return 0
+}
+
+func (x *WriteResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*WriteResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isWriteResponse_Result interface {
+	isWriteResponse_Result()
+}
+
+type WriteResponse_Written struct {
+	Written int32 `protobuf:"varint,1,opt,name=written,proto3,oneof"` // Bytes written
+}
+
+type WriteResponse_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*WriteResponse_Written) isWriteResponse_Result() {}
+
+func (*WriteResponse_Error) isWriteResponse_Result() {}
+
+// Truncate request/response
+type TruncateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`      // Relative to share root
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`     // New file size
+	Handle        int32                  `protobuf:"varint,3,opt,name=handle,proto3" json:"handle,omitempty"` // Optional open handle; used instead of path when set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TruncateRequest) Reset() {
+	*x = TruncateRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TruncateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateRequest) ProtoMessage() {}
+
+func (x *TruncateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateRequest.ProtoReflect.Descriptor instead.
+func (*TruncateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TruncateRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TruncateRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TruncateRequest) GetHandle() int32 {
+	if x != nil {
+		return x.Handle
+	}
+	return 0
+}
+
+type TruncateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TruncateResponse) Reset() {
+	*x = TruncateResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TruncateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TruncateResponse) ProtoMessage() {}
+
+func (x *TruncateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TruncateResponse.ProtoReflect.Descriptor instead.
+func (*TruncateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TruncateResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Chtimes request/response. A time is only changed if its set_ flag is; the
+// _now flags take the server's current time, as UTIME_NOW does.
+type ChtimesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
+	SetAtime      bool                   `protobuf:"varint,2,opt,name=set_atime,json=setAtime,proto3" json:"set_atime,omitempty"`
+	AtimeNow      bool                   `protobuf:"varint,3,opt,name=atime_now,json=atimeNow,proto3" json:"atime_now,omitempty"`
+	Atime         int64                  `protobuf:"varint,4,opt,name=atime,proto3" json:"atime,omitempty"`                          // Seconds since the epoch
+	AtimeNsec     uint32                 `protobuf:"varint,5,opt,name=atime_nsec,json=atimeNsec,proto3" json:"atime_nsec,omitempty"` // 0-999999999
+	SetMtime      bool                   `protobuf:"varint,6,opt,name=set_mtime,json=setMtime,proto3" json:"set_mtime,omitempty"`
+	MtimeNow      bool                   `protobuf:"varint,7,opt,name=mtime_now,json=mtimeNow,proto3" json:"mtime_now,omitempty"`
+	Mtime         int64                  `protobuf:"varint,8,opt,name=mtime,proto3" json:"mtime,omitempty"`                          // Seconds since the epoch
+	MtimeNsec     uint32                 `protobuf:"varint,9,opt,name=mtime_nsec,json=mtimeNsec,proto3" json:"mtime_nsec,omitempty"` // 0-999999999
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChtimesRequest) Reset() {
+	*x = ChtimesRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChtimesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChtimesRequest) ProtoMessage() {}
+
+func (x *ChtimesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChtimesRequest.ProtoReflect.Descriptor instead.
+func (*ChtimesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ChtimesRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ChtimesRequest) GetSetAtime() bool {
+	if x != nil {
+		return x.SetAtime
+	}
+	return false
+}
+
+func (x *ChtimesRequest) GetAtimeNow() bool {
+	if x != nil {
+		return x.AtimeNow
+	}
+	return false
+}
+
+func (x *ChtimesRequest) GetAtime() int64 {
+	if x != nil {
+		return x.Atime
+	}
+	return 0
+}
+
+func (x *ChtimesRequest) GetAtimeNsec() uint32 {
+	if x != nil {
+		return x.AtimeNsec
+	}
+	return 0
+}
+
+func (x *ChtimesRequest) GetSetMtime() bool {
+	if x != nil {
+		return x.SetMtime
+	}
+	return false
+}
+
+func (x *ChtimesRequest) GetMtimeNow() bool {
+	if x != nil {
+		return x.MtimeNow
+	}
+	return false
+}
+
+func (x *ChtimesRequest) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
+	}
+	return 0
+}
+
+func (x *ChtimesRequest) GetMtimeNsec() uint32 {
+	if x != nil {
+		return x.MtimeNsec
+	}
+	return 0
+}
+
+type ChtimesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChtimesResponse) Reset() {
+	*x = ChtimesResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChtimesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChtimesResponse) ProtoMessage() {}
+
+func (x *ChtimesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChtimesResponse.ProtoReflect.Descriptor instead.
+func (*ChtimesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ChtimesResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Mkdir request/response
+type MkdirRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`  // Relative to share root
+	Mode          uint32                 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"` // POSIX permissions for the new directory
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MkdirRequest) Reset() {
+	*x = MkdirRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MkdirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MkdirRequest) ProtoMessage() {}
+
+func (x *MkdirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MkdirRequest.ProtoReflect.Descriptor instead.
+func (*MkdirRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MkdirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MkdirRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+type MkdirResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*MkdirResponse_Info
+	//	*MkdirResponse_Error
+	Result        isMkdirResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MkdirResponse) Reset() {
+	*x = MkdirResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MkdirResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MkdirResponse) ProtoMessage() {}
+
+func (x *MkdirResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MkdirResponse.ProtoReflect.Descriptor instead.
+func (*MkdirResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MkdirResponse) GetResult() isMkdirResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *MkdirResponse) GetInfo() *FileInfo {
+	if x != nil {
+		if x, ok := x.Result.(*MkdirResponse_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *MkdirResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*MkdirResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isMkdirResponse_Result interface {
+	isMkdirResponse_Result()
+}
+
+type MkdirResponse_Info struct {
+	Info *FileInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"` // Attributes of the created directory
+}
+
+type MkdirResponse_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*MkdirResponse_Info) isMkdirResponse_Result() {}
+
+func (*MkdirResponse_Error) isMkdirResponse_Result() {}
+
+// Rmdir request/response
+type RmdirRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RmdirRequest) Reset() {
+	*x = RmdirRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RmdirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RmdirRequest) ProtoMessage() {}
+
+func (x *RmdirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RmdirRequest.ProtoReflect.Descriptor instead.
+func (*RmdirRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RmdirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RmdirResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RmdirResponse) Reset() {
+	*x = RmdirResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RmdirResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RmdirResponse) ProtoMessage() {}
+
+func (x *RmdirResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RmdirResponse.ProtoReflect.Descriptor instead.
+func (*RmdirResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RmdirResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Unlink request/response
+type UnlinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkRequest) Reset() {
+	*x = UnlinkRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkRequest) ProtoMessage() {}
+
+func (x *UnlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkRequest.ProtoReflect.Descriptor instead.
+func (*UnlinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UnlinkRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type UnlinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlinkResponse) Reset() {
+	*x = UnlinkResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlinkResponse) ProtoMessage() {}
+
+func (x *UnlinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlinkResponse.ProtoReflect.Descriptor instead.
+func (*UnlinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UnlinkResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Rename request/response
+type RenameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldPath       string                 `protobuf:"bytes,1,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"` // Relative to share root
+	NewPath       string                 `protobuf:"bytes,2,opt,name=new_path,json=newPath,proto3" json:"new_path,omitempty"` // Relative to share root
+	Flags         uint32                 `protobuf:"varint,3,opt,name=flags,proto3" json:"flags,omitempty"`                   // Linux renameat2(2) flags: RENAME_NOREPLACE (1), RENAME_EXCHANGE (2)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameRequest) Reset() {
+	*x = RenameRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameRequest) ProtoMessage() {}
+
+func (x *RenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameRequest.ProtoReflect.Descriptor instead.
+func (*RenameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RenameRequest) GetOldPath() string {
+	if x != nil {
+		return x.OldPath
+	}
+	return ""
+}
+
+func (x *RenameRequest) GetNewPath() string {
+	if x != nil {
+		return x.NewPath
+	}
+	return ""
+}
+
+func (x *RenameRequest) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+type RenameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         *Error                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameResponse) Reset() {
+	*x = RenameResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameResponse) ProtoMessage() {}
+
+func (x *RenameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameResponse.ProtoReflect.Descriptor instead.
+func (*RenameResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RenameResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Readlink request/response. Targets are '/'-separated and relative to the
+// link's directory; targets outside the share are not reported.
+type ReadlinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadlinkRequest) Reset() {
+	*x = ReadlinkRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadlinkRequest) ProtoMessage() {}
+
+func (x *ReadlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadlinkRequest.ProtoReflect.Descriptor instead.
+func (*ReadlinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReadlinkRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ReadlinkResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ReadlinkResponse_Target
+	//	*ReadlinkResponse_Error
+	Result        isReadlinkResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadlinkResponse) Reset() {
+	*x = ReadlinkResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadlinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadlinkResponse) ProtoMessage() {}
+
+func (x *ReadlinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadlinkResponse.ProtoReflect.Descriptor instead.
+func (*ReadlinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReadlinkResponse) GetResult() isReadlinkResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ReadlinkResponse) GetTarget() string {
+	if x != nil {
+		if x, ok := x.Result.(*ReadlinkResponse_Target); ok {
+			return x.Target
+		}
+	}
+	return ""
+}
+
+func (x *ReadlinkResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*ReadlinkResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isReadlinkResponse_Result interface {
+	isReadlinkResponse_Result()
+}
+
+type ReadlinkResponse_Target struct {
+	Target string `protobuf:"bytes,1,opt,name=target,proto3,oneof"`
+}
+
+type ReadlinkResponse_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*ReadlinkResponse_Target) isReadlinkResponse_Result() {}
+
+func (*ReadlinkResponse_Error) isReadlinkResponse_Result() {}
+
+// Symlink request/response. The target must be relative and stay within the
+// share; absolute targets are rejected with EPERM.
+type SymlinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`     // Link to create, relative to share root
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"` // '/'-separated, relative to the link's directory
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymlinkRequest) Reset() {
+	*x = SymlinkRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymlinkRequest) ProtoMessage() {}
+
+func (x *SymlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymlinkRequest.ProtoReflect.Descriptor instead.
+func (*SymlinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SymlinkRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SymlinkRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type SymlinkResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*SymlinkResponse_Info
+	//	*SymlinkResponse_Error
+	Result        isSymlinkResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymlinkResponse) Reset() {
+	*x = SymlinkResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymlinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymlinkResponse) ProtoMessage() {}
+
+func (x *SymlinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymlinkResponse.ProtoReflect.Descriptor instead.
+func (*SymlinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SymlinkResponse) GetResult() isSymlinkResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *SymlinkResponse) GetInfo() *FileInfo {
+	if x != nil {
+		if x, ok := x.Result.(*SymlinkResponse_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *SymlinkResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*SymlinkResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isSymlinkResponse_Result interface {
+	isSymlinkResponse_Result()
+}
+
+type SymlinkResponse_Info struct {
+	Info *FileInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"` // Attributes of the new link
+}
+
+type SymlinkResponse_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*SymlinkResponse_Info) isSymlinkResponse_Result() {}
+
+func (*SymlinkResponse_Error) isSymlinkResponse_Result() {}
+
+// Statfs request/response
+type StatfsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Any path in the share; selects the volume when mounts are nested
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatfsRequest) Reset() {
+	*x = StatfsRequest{}
+	mi := &file_proto_fsdriver_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatfsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatfsRequest) ProtoMessage() {}
+
+func (x *StatfsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatfsRequest.ProtoReflect.Descriptor instead.
+func (*StatfsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *StatfsRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// Volume statistics. Byte counts are exact; clients derive block counts from
+// block_size. File counts are 0 where the volume has no fixed inode table.
+type FsStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TotalBytes     uint64                 `protobuf:"varint,1,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	FreeBytes      uint64                 `protobuf:"varint,2,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"`                // Free space including space reserved for the superuser
+	AvailableBytes uint64                 `protobuf:"varint,3,opt,name=available_bytes,json=availableBytes,proto3" json:"available_bytes,omitempty"` // Free space available to the server process
+	TotalFiles     uint64                 `protobuf:"varint,4,opt,name=total_files,json=totalFiles,proto3" json:"total_files,omitempty"`
+	FreeFiles      uint64                 `protobuf:"varint,5,opt,name=free_files,json=freeFiles,proto3" json:"free_files,omitempty"`
+	BlockSize      uint32                 `protobuf:"varint,6,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`               // Allocation unit of the volume
+	MaxNameLength  uint32                 `protobuf:"varint,7,opt,name=max_name_length,json=maxNameLength,proto3" json:"max_name_length,omitempty"` // Longest file name component
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FsStats) Reset() {
+	*x = FsStats{}
+	mi := &file_proto_fsdriver_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FsStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FsStats) ProtoMessage() {}
+
+func (x *FsStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FsStats.ProtoReflect.Descriptor instead.
+func (*FsStats) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *FsStats) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *FsStats) GetFreeBytes() uint64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+func (x *FsStats) GetAvailableBytes() uint64 {
+	if x != nil {
+		return x.AvailableBytes
+	}
+	return 0
+}
+
+func (x *FsStats) GetTotalFiles() uint64 {
+	if x != nil {
+		return x.TotalFiles
+	}
+	return 0
+}
+
+func (x *FsStats) GetFreeFiles() uint64 {
+	if x != nil {
+		return x.FreeFiles
+	}
+	return 0
+}
+
+func (x *FsStats) GetBlockSize() uint32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *FsStats) GetMaxNameLength() uint32 {
+	if x != nil {
+		return x.MaxNameLength
+	}
+	return 0
+}
+
+type StatfsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*StatfsResponse_Stats
+	//	*StatfsResponse_Error
+	Result        isStatfsResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatfsResponse) Reset() {
+	*x = StatfsResponse{}
+	mi := &file_proto_fsdriver_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatfsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatfsResponse) ProtoMessage() {}
+
+func (x *StatfsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatfsResponse.ProtoReflect.Descriptor instead.
+func (*StatfsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *StatfsResponse) GetResult() isStatfsResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *StatfsResponse) GetStats() *FsStats {
+	if x != nil {
+		if x, ok := x.Result.(*StatfsResponse_Stats); ok {
+			return x.Stats
+		}
+	}
+	return nil
+}
+
+func (x *StatfsResponse) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*StatfsResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isStatfsResponse_Result interface {
+	isStatfsResponse_Result()
+}
+
+type StatfsResponse_Stats struct {
+	Stats *FsStats `protobuf:"bytes,1,opt,name=stats,proto3,oneof"`
+}
+
+type StatfsResponse_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*StatfsResponse_Stats) isStatfsResponse_Result() {}
+
+func (*StatfsResponse_Error) isStatfsResponse_Result() {}
+
+// Watch request (client to server). SUBSCRIBE adds a subscription and is
+// answered with a WatchAck carrying its ID; UNSUBSCRIBE removes one.
 type WatchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`            // Directory to watch (relative to share root)
-	Recursive     bool                   `protobuf:"varint,2,opt,name=recursive,proto3" json:"recursive,omitempty"` // Watch subdirectories
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`            // Directory to watch (relative to share root)
+	Recursive      bool                   `protobuf:"varint,2,opt,name=recursive,proto3" json:"recursive,omitempty"` // Watch subdirectories
+	Op             WatchOperation         `protobuf:"varint,3,opt,name=op,proto3,enum=fsdriver.WatchOperation" json:"op,omitempty"`
+	SubscriptionId uint64                 `protobuf:"varint,4,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"` // UNSUBSCRIBE: subscription to remove
+	// SUBSCRIBE filters. Globs match share-relative paths with '/' separators;
+	// "*" matches within one component, "**" any number of components.
+	EventTypes    []WatchEventType `protobuf:"varint,5,rep,packed,name=event_types,json=eventTypes,proto3,enum=fsdriver.WatchEventType" json:"event_types,omitempty"` // Empty = all types
+	Include       []string         `protobuf:"bytes,6,rep,name=include,proto3" json:"include,omitempty"`                                                              // Empty = everything under path
+	Exclude       []string         `protobuf:"bytes,7,rep,name=exclude,proto3" json:"exclude,omitempty"`                                                              // e.g. "**/.git/**", "**/node_modules/**"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WatchRequest) Reset() {
 	*x = WatchRequest{}
-	mi := &file_proto_fsdriver_proto_msgTypes[12]
+	mi := &file_proto_fsdriver_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -895,7 +2735,7 @@ func (x *WatchRequest) String() string {
 func (*WatchRequest) ProtoMessage() {}
 
 func (x *WatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[12]
+	mi := &file_proto_fsdriver_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -908,7 +2748,7 @@ func (x *WatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
 func (*WatchRequest) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{12}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *WatchRequest) GetPath() string {
@@ -925,20 +2765,64 @@ func (x *WatchRequest) GetRecursive() bool {
 	return false
 }
 
-// Watch event (server to client)
+func (x *WatchRequest) GetOp() WatchOperation {
+	if x != nil {
+		return x.Op
+	}
+	return WatchOperation_SUBSCRIBE
+}
+
+func (x *WatchRequest) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *WatchRequest) GetEventTypes() []WatchEventType {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *WatchRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+// Watch event (server to client). Either a change (path, type, old_path) or,
+// with type UNKNOWN and no path, one of the control messages.
 type WatchEvent struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
-	Type          WatchEventType         `protobuf:"varint,2,opt,name=type,proto3,enum=fsdriver.WatchEventType" json:"type,omitempty"`
-	OldPath       string                 `protobuf:"bytes,3,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"` // For rename events
-	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`           // Unix timestamp
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Path            string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // Relative to share root
+	Type            WatchEventType         `protobuf:"varint,2,opt,name=type,proto3,enum=fsdriver.WatchEventType" json:"type,omitempty"`
+	OldPath         string                 `protobuf:"bytes,3,opt,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`                                 // RENAME only: previous path; path is the new one
+	Timestamp       int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                           // Unix timestamp
+	SubscriptionIds []uint64               `protobuf:"varint,9,rep,packed,name=subscription_ids,json=subscriptionIds,proto3" json:"subscription_ids,omitempty"` // Subscriptions the change matched
+	// Types that are valid to be assigned to Control:
+	//
+	//	*WatchEvent_Ack
+	//	*WatchEvent_Error
+	//	*WatchEvent_Overflow
+	//	*WatchEvent_Heartbeat
+	Control       isWatchEvent_Control `protobuf_oneof:"control"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WatchEvent) Reset() {
 	*x = WatchEvent{}
-	mi := &file_proto_fsdriver_proto_msgTypes[13]
+	mi := &file_proto_fsdriver_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -950,7 +2834,7 @@ func (x *WatchEvent) String() string {
 func (*WatchEvent) ProtoMessage() {}
 
 func (x *WatchEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_fsdriver_proto_msgTypes[13]
+	mi := &file_proto_fsdriver_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -963,7 +2847,7 @@ func (x *WatchEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
 func (*WatchEvent) Descriptor() ([]byte, []int) {
-	return file_proto_fsdriver_proto_rawDescGZIP(), []int{13}
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *WatchEvent) GetPath() string {
@@ -994,11 +2878,305 @@ func (x *WatchEvent) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *WatchEvent) GetSubscriptionIds() []uint64 {
+	if x != nil {
+		return x.SubscriptionIds
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetControl() isWatchEvent_Control {
+	if x != nil {
+		return x.Control
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetAck() *WatchAck {
+	if x != nil {
+		if x, ok := x.Control.(*WatchEvent_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetError() *WatchError {
+	if x != nil {
+		if x, ok := x.Control.(*WatchEvent_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetOverflow() *WatchOverflow {
+	if x != nil {
+		if x, ok := x.Control.(*WatchEvent_Overflow); ok {
+			return x.Overflow
+		}
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetHeartbeat() *WatchHeartbeat {
+	if x != nil {
+		if x, ok := x.Control.(*WatchEvent_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+type isWatchEvent_Control interface {
+	isWatchEvent_Control()
+}
+
+type WatchEvent_Ack struct {
+	Ack *WatchAck `protobuf:"bytes,5,opt,name=ack,proto3,oneof"`
+}
+
+type WatchEvent_Error struct {
+	Error *WatchError `protobuf:"bytes,6,opt,name=error,proto3,oneof"`
+}
+
+type WatchEvent_Overflow struct {
+	Overflow *WatchOverflow `protobuf:"bytes,7,opt,name=overflow,proto3,oneof"`
+}
+
+type WatchEvent_Heartbeat struct {
+	Heartbeat *WatchHeartbeat `protobuf:"bytes,8,opt,name=heartbeat,proto3,oneof"`
+}
+
+func (*WatchEvent_Ack) isWatchEvent_Control() {}
+
+func (*WatchEvent_Error) isWatchEvent_Control() {}
+
+func (*WatchEvent_Overflow) isWatchEvent_Control() {}
+
+func (*WatchEvent_Heartbeat) isWatchEvent_Control() {}
+
+// Confirms a WatchRequest; events for its path follow.
+type WatchAck struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId      uint64                 `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Path                string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	HeartbeatIntervalMs int32                  `protobuf:"varint,3,opt,name=heartbeat_interval_ms,json=heartbeatIntervalMs,proto3" json:"heartbeat_interval_ms,omitempty"` // The server sends a heartbeat at least this often
+	Unsubscribed        bool                   `protobuf:"varint,4,opt,name=unsubscribed,proto3" json:"unsubscribed,omitempty"`                                            // Confirms an UNSUBSCRIBE instead
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *WatchAck) Reset() {
+	*x = WatchAck{}
+	mi := &file_proto_fsdriver_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAck) ProtoMessage() {}
+
+func (x *WatchAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAck.ProtoReflect.Descriptor instead.
+func (*WatchAck) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *WatchAck) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *WatchAck) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WatchAck) GetHeartbeatIntervalMs() int32 {
+	if x != nil {
+		return x.HeartbeatIntervalMs
+	}
+	return 0
+}
+
+func (x *WatchAck) GetUnsubscribed() bool {
+	if x != nil {
+		return x.Unsubscribed
+	}
+	return false
+}
+
+// A subscription failed, or the server's watcher reported an error.
+type WatchError struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId uint64                 `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"` // 0 if not tied to a subscription
+	Path           string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Error          *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchError) Reset() {
+	*x = WatchError{}
+	mi := &file_proto_fsdriver_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchError) ProtoMessage() {}
+
+func (x *WatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchError.ProtoReflect.Descriptor instead.
+func (*WatchError) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *WatchError) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *WatchError) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WatchError) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// Events were lost; cached state below path must be considered stale.
+type WatchOverflow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // "" for the whole share
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOverflow) Reset() {
+	*x = WatchOverflow{}
+	mi := &file_proto_fsdriver_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOverflow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOverflow) ProtoMessage() {}
+
+func (x *WatchOverflow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOverflow.ProtoReflect.Descriptor instead.
+func (*WatchOverflow) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *WatchOverflow) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+// Keeps idle streams alive and lets the client detect a dead connection.
+type WatchHeartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchHeartbeat) Reset() {
+	*x = WatchHeartbeat{}
+	mi := &file_proto_fsdriver_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchHeartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchHeartbeat) ProtoMessage() {}
+
+func (x *WatchHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_fsdriver_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchHeartbeat.ProtoReflect.Descriptor instead.
+func (*WatchHeartbeat) Descriptor() ([]byte, []int) {
+	return file_proto_fsdriver_proto_rawDescGZIP(), []int{44}
+}
+
 var File_proto_fsdriver_proto protoreflect.FileDescriptor
 
 const file_proto_fsdriver_proto_rawDesc = "" +
 	"\n" +
-	"\x14proto/fsdriver.proto\x12\bfsdriver\"\xfd\x01\n" +
+	"\x14proto/fsdriver.proto\x12\bfsdriver\"9\n" +
+	"\x13CapabilitiesRequest\x12\"\n" +
+	"\rmax_read_size\x18\x01 \x01(\x05R\vmaxReadSize\":\n" +
+	"\x14CapabilitiesResponse\x12\"\n" +
+	"\rmax_read_size\x18\x01 \x01(\x05R\vmaxReadSize\"\xf6\x03\n" +
 	"\bFileInfo\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x15\n" +
 	"\x06is_dir\x18\x02 \x01(\bR\x05isDir\x12\x12\n" +
@@ -1013,26 +3191,40 @@ const file_proto_fsdriver_proto_rawDesc = "" +
 	"\x03gid\x18\t \x01(\rR\x03gid\x12\x1d\n" +
 	"\n" +
 	"is_symlink\x18\n" +
-	" \x01(\bR\tisSymlink\"T\n" +
+	" \x01(\bR\tisSymlink\x12\x10\n" +
+	"\x03dev\x18\v \x01(\x04R\x03dev\x12\x10\n" +
+	"\x03ino\x18\f \x01(\x04R\x03ino\x12\x14\n" +
+	"\x05nlink\x18\r \x01(\rR\x05nlink\x12\"\n" +
+	"\rmod_time_nsec\x18\x0e \x01(\rR\vmodTimeNsec\x12(\n" +
+	"\x10access_time_nsec\x18\x0f \x01(\rR\x0eaccessTimeNsec\x12(\n" +
+	"\x10change_time_nsec\x18\x10 \x01(\rR\x0echangeTimeNsec\x12\x1d\n" +
+	"\n" +
+	"birth_time\x18\x11 \x01(\x03R\tbirthTime\x12&\n" +
+	"\x0fbirth_time_nsec\x18\x12 \x01(\rR\rbirthTimeNsec\"{\n" +
 	"\x05Error\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
 	"\n" +
-	"win32_code\x18\x03 \x01(\x05R\twin32Code\"!\n" +
+	"win32_code\x18\x03 \x01(\x05R\twin32Code\x12%\n" +
+	"\x0eunknown_handle\x18\x04 \x01(\bR\runknownHandle\"!\n" +
 	"\vStatRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\"k\n" +
 	"\fStatResponse\x12(\n" +
 	"\x04info\x18\x01 \x01(\v2\x12.fsdriver.FileInfoH\x00R\x04info\x12'\n" +
 	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
-	"\x06result\"R\n" +
+	"\x06result\"\x88\x01\n" +
 	"\x0eReadDirRequest\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\x81\x01\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1a\n" +
+	"\x06offset\x18\x02 \x01(\x05B\x02\x18\x01R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x18\n" +
+	"\arelease\x18\x05 \x01(\bR\arelease\"\xa2\x01\n" +
 	"\x0fReadDirResponse\x12,\n" +
 	"\aentries\x18\x01 \x03(\v2\x12.fsdriver.FileInfoR\aentries\x12\x19\n" +
 	"\bhas_more\x18\x02 \x01(\bR\ahasMore\x12%\n" +
-	"\x05error\x18\x03 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"7\n" +
+	"\x05error\x18\x03 \x01(\v2\x0f.fsdriver.ErrorR\x05error\x12\x1f\n" +
+	"\vnext_cursor\x18\x04 \x01(\tR\n" +
+	"nextCursor\"7\n" +
 	"\vOpenRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
 	"\x05flags\x18\x02 \x01(\x05R\x05flags\"[\n" +
@@ -1047,20 +3239,148 @@ const file_proto_fsdriver_proto_rawDesc = "" +
 	"\fReadResponse\x12\x14\n" +
 	"\x04data\x18\x01 \x01(\fH\x00R\x04data\x12'\n" +
 	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"z\n" +
+	"\x11ReadStreamRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\x05R\x06handle\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x03R\x06length\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x04 \x01(\x05R\tchunkSize\"l\n" +
+	"\tReadChunk\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x04data\x18\x02 \x01(\fH\x00R\x04data\x12'\n" +
+	"\x05error\x18\x03 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
 	"\x06result\"&\n" +
 	"\fCloseRequest\x12\x16\n" +
 	"\x06handle\x18\x01 \x01(\x05R\x06handle\"6\n" +
 	"\rCloseResponse\x12%\n" +
-	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"@\n" +
+	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"M\n" +
+	"\rCreateRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05flags\x18\x02 \x01(\x05R\x05flags\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\rR\x04mode\"w\n" +
+	"\x0eCreateResponse\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\x05R\x06handle\x12&\n" +
+	"\x04info\x18\x02 \x01(\v2\x12.fsdriver.FileInfoR\x04info\x12%\n" +
+	"\x05error\x18\x03 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"R\n" +
+	"\fWriteRequest\x12\x16\n" +
+	"\x06handle\x18\x01 \x01(\x05R\x06handle\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"^\n" +
+	"\rWriteResponse\x12\x1a\n" +
+	"\awritten\x18\x01 \x01(\x05H\x00R\awritten\x12'\n" +
+	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"Q\n" +
+	"\x0fTruncateRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06handle\x18\x03 \x01(\x05R\x06handle\"9\n" +
+	"\x10TruncateResponse\x12%\n" +
+	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"\x82\x02\n" +
+	"\x0eChtimesRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1b\n" +
+	"\tset_atime\x18\x02 \x01(\bR\bsetAtime\x12\x1b\n" +
+	"\tatime_now\x18\x03 \x01(\bR\batimeNow\x12\x14\n" +
+	"\x05atime\x18\x04 \x01(\x03R\x05atime\x12\x1d\n" +
+	"\n" +
+	"atime_nsec\x18\x05 \x01(\rR\tatimeNsec\x12\x1b\n" +
+	"\tset_mtime\x18\x06 \x01(\bR\bsetMtime\x12\x1b\n" +
+	"\tmtime_now\x18\a \x01(\bR\bmtimeNow\x12\x14\n" +
+	"\x05mtime\x18\b \x01(\x03R\x05mtime\x12\x1d\n" +
+	"\n" +
+	"mtime_nsec\x18\t \x01(\rR\tmtimeNsec\"8\n" +
+	"\x0fChtimesResponse\x12%\n" +
+	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"6\n" +
+	"\fMkdirRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\rR\x04mode\"l\n" +
+	"\rMkdirResponse\x12(\n" +
+	"\x04info\x18\x01 \x01(\v2\x12.fsdriver.FileInfoH\x00R\x04info\x12'\n" +
+	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"\"\n" +
+	"\fRmdirRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"6\n" +
+	"\rRmdirResponse\x12%\n" +
+	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"#\n" +
+	"\rUnlinkRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"7\n" +
+	"\x0eUnlinkResponse\x12%\n" +
+	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"[\n" +
+	"\rRenameRequest\x12\x19\n" +
+	"\bold_path\x18\x01 \x01(\tR\aoldPath\x12\x19\n" +
+	"\bnew_path\x18\x02 \x01(\tR\anewPath\x12\x14\n" +
+	"\x05flags\x18\x03 \x01(\rR\x05flags\"7\n" +
+	"\x0eRenameResponse\x12%\n" +
+	"\x05error\x18\x01 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"%\n" +
+	"\x0fReadlinkRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"_\n" +
+	"\x10ReadlinkResponse\x12\x18\n" +
+	"\x06target\x18\x01 \x01(\tH\x00R\x06target\x12'\n" +
+	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"<\n" +
+	"\x0eSymlinkRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\"n\n" +
+	"\x0fSymlinkResponse\x12(\n" +
+	"\x04info\x18\x01 \x01(\v2\x12.fsdriver.FileInfoH\x00R\x04info\x12'\n" +
+	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"#\n" +
+	"\rStatfsRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\xf9\x01\n" +
+	"\aFsStats\x12\x1f\n" +
+	"\vtotal_bytes\x18\x01 \x01(\x04R\n" +
+	"totalBytes\x12\x1d\n" +
+	"\n" +
+	"free_bytes\x18\x02 \x01(\x04R\tfreeBytes\x12'\n" +
+	"\x0favailable_bytes\x18\x03 \x01(\x04R\x0eavailableBytes\x12\x1f\n" +
+	"\vtotal_files\x18\x04 \x01(\x04R\n" +
+	"totalFiles\x12\x1d\n" +
+	"\n" +
+	"free_files\x18\x05 \x01(\x04R\tfreeFiles\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x06 \x01(\rR\tblockSize\x12&\n" +
+	"\x0fmax_name_length\x18\a \x01(\rR\rmaxNameLength\"n\n" +
+	"\x0eStatfsResponse\x12)\n" +
+	"\x05stats\x18\x01 \x01(\v2\x11.fsdriver.FsStatsH\x00R\x05stats\x12'\n" +
+	"\x05error\x18\x02 \x01(\v2\x0f.fsdriver.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"\x82\x02\n" +
 	"\fWatchRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1c\n" +
-	"\trecursive\x18\x02 \x01(\bR\trecursive\"\x87\x01\n" +
+	"\trecursive\x18\x02 \x01(\bR\trecursive\x12(\n" +
+	"\x02op\x18\x03 \x01(\x0e2\x18.fsdriver.WatchOperationR\x02op\x12'\n" +
+	"\x0fsubscription_id\x18\x04 \x01(\x04R\x0esubscriptionId\x129\n" +
+	"\vevent_types\x18\x05 \x03(\x0e2\x18.fsdriver.WatchEventTypeR\n" +
+	"eventTypes\x12\x18\n" +
+	"\ainclude\x18\x06 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\a \x03(\tR\aexclude\"\x84\x03\n" +
 	"\n" +
 	"WatchEvent\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\x12,\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x18.fsdriver.WatchEventTypeR\x04type\x12\x19\n" +
 	"\bold_path\x18\x03 \x01(\tR\aoldPath\x12\x1c\n" +
-	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp*Y\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12)\n" +
+	"\x10subscription_ids\x18\t \x03(\x04R\x0fsubscriptionIds\x12&\n" +
+	"\x03ack\x18\x05 \x01(\v2\x12.fsdriver.WatchAckH\x00R\x03ack\x12,\n" +
+	"\x05error\x18\x06 \x01(\v2\x14.fsdriver.WatchErrorH\x00R\x05error\x125\n" +
+	"\boverflow\x18\a \x01(\v2\x17.fsdriver.WatchOverflowH\x00R\boverflow\x128\n" +
+	"\theartbeat\x18\b \x01(\v2\x18.fsdriver.WatchHeartbeatH\x00R\theartbeatB\t\n" +
+	"\acontrol\"\x9f\x01\n" +
+	"\bWatchAck\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x04R\x0esubscriptionId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x122\n" +
+	"\x15heartbeat_interval_ms\x18\x03 \x01(\x05R\x13heartbeatIntervalMs\x12\"\n" +
+	"\funsubscribed\x18\x04 \x01(\bR\funsubscribed\"p\n" +
+	"\n" +
+	"WatchError\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x04R\x0esubscriptionId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12%\n" +
+	"\x05error\x18\x03 \x01(\v2\x0f.fsdriver.ErrorR\x05error\"#\n" +
+	"\rWatchOverflow\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\x10\n" +
+	"\x0eWatchHeartbeat*0\n" +
+	"\x0eWatchOperation\x12\r\n" +
+	"\tSUBSCRIBE\x10\x00\x12\x0f\n" +
+	"\vUNSUBSCRIBE\x10\x01*Y\n" +
 	"\x0eWatchEventType\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\n" +
 	"\n" +
@@ -1072,13 +3392,27 @@ const file_proto_fsdriver_proto_rawDesc = "" +
 	"\n" +
 	"\x06RENAME\x10\x04\x12\n" +
 	"\n" +
-	"\x06ATTRIB\x10\x052\xed\x02\n" +
-	"\x11FileSystemService\x125\n" +
+	"\x06ATTRIB\x10\x052\xa9\t\n" +
+	"\x11FileSystemService\x12P\n" +
+	"\x0fGetCapabilities\x12\x1d.fsdriver.CapabilitiesRequest\x1a\x1e.fsdriver.CapabilitiesResponse\x125\n" +
 	"\x04Stat\x12\x15.fsdriver.StatRequest\x1a\x16.fsdriver.StatResponse\x12>\n" +
 	"\aReadDir\x12\x18.fsdriver.ReadDirRequest\x1a\x19.fsdriver.ReadDirResponse\x125\n" +
 	"\x04Open\x12\x15.fsdriver.OpenRequest\x1a\x16.fsdriver.OpenResponse\x125\n" +
-	"\x04Read\x12\x15.fsdriver.ReadRequest\x1a\x16.fsdriver.ReadResponse\x128\n" +
-	"\x05Close\x12\x16.fsdriver.CloseRequest\x1a\x17.fsdriver.CloseResponse\x129\n" +
+	"\x04Read\x12\x15.fsdriver.ReadRequest\x1a\x16.fsdriver.ReadResponse\x12@\n" +
+	"\n" +
+	"ReadStream\x12\x1b.fsdriver.ReadStreamRequest\x1a\x13.fsdriver.ReadChunk0\x01\x128\n" +
+	"\x05Close\x12\x16.fsdriver.CloseRequest\x1a\x17.fsdriver.CloseResponse\x12;\n" +
+	"\x06Create\x12\x17.fsdriver.CreateRequest\x1a\x18.fsdriver.CreateResponse\x128\n" +
+	"\x05Write\x12\x16.fsdriver.WriteRequest\x1a\x17.fsdriver.WriteResponse\x12A\n" +
+	"\bTruncate\x12\x19.fsdriver.TruncateRequest\x1a\x1a.fsdriver.TruncateResponse\x12>\n" +
+	"\aChtimes\x12\x18.fsdriver.ChtimesRequest\x1a\x19.fsdriver.ChtimesResponse\x128\n" +
+	"\x05Mkdir\x12\x16.fsdriver.MkdirRequest\x1a\x17.fsdriver.MkdirResponse\x128\n" +
+	"\x05Rmdir\x12\x16.fsdriver.RmdirRequest\x1a\x17.fsdriver.RmdirResponse\x12;\n" +
+	"\x06Unlink\x12\x17.fsdriver.UnlinkRequest\x1a\x18.fsdriver.UnlinkResponse\x12;\n" +
+	"\x06Rename\x12\x17.fsdriver.RenameRequest\x1a\x18.fsdriver.RenameResponse\x12A\n" +
+	"\bReadlink\x12\x19.fsdriver.ReadlinkRequest\x1a\x1a.fsdriver.ReadlinkResponse\x12>\n" +
+	"\aSymlink\x12\x18.fsdriver.SymlinkRequest\x1a\x19.fsdriver.SymlinkResponse\x12;\n" +
+	"\x06Statfs\x12\x17.fsdriver.StatfsRequest\x1a\x18.fsdriver.StatfsResponse\x129\n" +
 	"\x05Watch\x12\x16.fsdriver.WatchRequest\x1a\x14.fsdriver.WatchEvent(\x010\x01B#Z!github.com/example/fsdriver/protob\x06proto3"
 
 var (
@@ -1093,51 +3427,132 @@ func file_proto_fsdriver_proto_rawDescGZIP() []byte {
 	return file_proto_fsdriver_proto_rawDescData
 }
 
-var file_proto_fsdriver_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_fsdriver_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_fsdriver_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_fsdriver_proto_msgTypes = make([]protoimpl.MessageInfo, 45)
 var file_proto_fsdriver_proto_goTypes = []any{
-	(WatchEventType)(0),     // 0: fsdriver.WatchEventType
-	(*FileInfo)(nil),        // 1: fsdriver.FileInfo
-	(*Error)(nil),           // 2: fsdriver.Error
-	(*StatRequest)(nil),     // 3: fsdriver.StatRequest
-	(*StatResponse)(nil),    // 4: fsdriver.StatResponse
-	(*ReadDirRequest)(nil),  // 5: fsdriver.ReadDirRequest
-	(*ReadDirResponse)(nil), // 6: fsdriver.ReadDirResponse
-	(*OpenRequest)(nil),     // 7: fsdriver.OpenRequest
-	(*OpenResponse)(nil),    // 8: fsdriver.OpenResponse
-	(*ReadRequest)(nil),     // 9: fsdriver.ReadRequest
-	(*ReadResponse)(nil),    // 10: fsdriver.ReadResponse
-	(*CloseRequest)(nil),    // 11: fsdriver.CloseRequest
-	(*CloseResponse)(nil),   // 12: fsdriver.CloseResponse
-	(*WatchRequest)(nil),    // 13: fsdriver.WatchRequest
-	(*WatchEvent)(nil),      // 14: fsdriver.WatchEvent
+	(WatchOperation)(0),          // 0: fsdriver.WatchOperation
+	(WatchEventType)(0),          // 1: fsdriver.WatchEventType
+	(*CapabilitiesRequest)(nil),  // 2: fsdriver.CapabilitiesRequest
+	(*CapabilitiesResponse)(nil), // 3: fsdriver.CapabilitiesResponse
+	(*FileInfo)(nil),             // 4: fsdriver.FileInfo
+	(*Error)(nil),                // 5: fsdriver.Error
+	(*StatRequest)(nil),          // 6: fsdriver.StatRequest
+	(*StatResponse)(nil),         // 7: fsdriver.StatResponse
+	(*ReadDirRequest)(nil),       // 8: fsdriver.ReadDirRequest
+	(*ReadDirResponse)(nil),      // 9: fsdriver.ReadDirResponse
+	(*OpenRequest)(nil),          // 10: fsdriver.OpenRequest
+	(*OpenResponse)(nil),         // 11: fsdriver.OpenResponse
+	(*ReadRequest)(nil),          // 12: fsdriver.ReadRequest
+	(*ReadResponse)(nil),         // 13: fsdriver.ReadResponse
+	(*ReadStreamRequest)(nil),    // 14: fsdriver.ReadStreamRequest
+	(*ReadChunk)(nil),            // 15: fsdriver.ReadChunk
+	(*CloseRequest)(nil),         // 16: fsdriver.CloseRequest
+	(*CloseResponse)(nil),        // 17: fsdriver.CloseResponse
+	(*CreateRequest)(nil),        // 18: fsdriver.CreateRequest
+	(*CreateResponse)(nil),       // 19: fsdriver.CreateResponse
+	(*WriteRequest)(nil),         // 20: fsdriver.WriteRequest
+	(*WriteResponse)(nil),        // 21: fsdriver.WriteResponse
+	(*TruncateRequest)(nil),      // 22: fsdriver.TruncateRequest
+	(*TruncateResponse)(nil),     // 23: fsdriver.TruncateResponse
+	(*ChtimesRequest)(nil),       // 24: fsdriver.ChtimesRequest
+	(*ChtimesResponse)(nil),      // 25: fsdriver.ChtimesResponse
+	(*MkdirRequest)(nil),         // 26: fsdriver.MkdirRequest
+	(*MkdirResponse)(nil),        // 27: fsdriver.MkdirResponse
+	(*RmdirRequest)(nil),         // 28: fsdriver.RmdirRequest
+	(*RmdirResponse)(nil),        // 29: fsdriver.RmdirResponse
+	(*UnlinkRequest)(nil),        // 30: fsdriver.UnlinkRequest
+	(*UnlinkResponse)(nil),       // 31: fsdriver.UnlinkResponse
+	(*RenameRequest)(nil),        // 32: fsdriver.RenameRequest
+	(*RenameResponse)(nil),       // 33: fsdriver.RenameResponse
+	(*ReadlinkRequest)(nil),      // 34: fsdriver.ReadlinkRequest
+	(*ReadlinkResponse)(nil),     // 35: fsdriver.ReadlinkResponse
+	(*SymlinkRequest)(nil),       // 36: fsdriver.SymlinkRequest
+	(*SymlinkResponse)(nil),      // 37: fsdriver.SymlinkResponse
+	(*StatfsRequest)(nil),        // 38: fsdriver.StatfsRequest
+	(*FsStats)(nil),              // 39: fsdriver.FsStats
+	(*StatfsResponse)(nil),       // 40: fsdriver.StatfsResponse
+	(*WatchRequest)(nil),         // 41: fsdriver.WatchRequest
+	(*WatchEvent)(nil),           // 42: fsdriver.WatchEvent
+	(*WatchAck)(nil),             // 43: fsdriver.WatchAck
+	(*WatchError)(nil),           // 44: fsdriver.WatchError
+	(*WatchOverflow)(nil),        // 45: fsdriver.WatchOverflow
+	(*WatchHeartbeat)(nil),       // 46: fsdriver.WatchHeartbeat
 }
 var file_proto_fsdriver_proto_depIdxs = []int32{
-	1,  // 0: fsdriver.StatResponse.info:type_name -> fsdriver.FileInfo
-	2,  // 1: fsdriver.StatResponse.error:type_name -> fsdriver.Error
-	1,  // 2: fsdriver.ReadDirResponse.entries:type_name -> fsdriver.FileInfo
-	2,  // 3: fsdriver.ReadDirResponse.error:type_name -> fsdriver.Error
-	2,  // 4: fsdriver.OpenResponse.error:type_name -> fsdriver.Error
-	2,  // 5: fsdriver.ReadResponse.error:type_name -> fsdriver.Error
-	2,  // 6: fsdriver.CloseResponse.error:type_name -> fsdriver.Error
-	0,  // 7: fsdriver.WatchEvent.type:type_name -> fsdriver.WatchEventType
-	3,  // 8: fsdriver.FileSystemService.Stat:input_type -> fsdriver.StatRequest
-	5,  // 9: fsdriver.FileSystemService.ReadDir:input_type -> fsdriver.ReadDirRequest
-	7,  // 10: fsdriver.FileSystemService.Open:input_type -> fsdriver.OpenRequest
-	9,  // 11: fsdriver.FileSystemService.Read:input_type -> fsdriver.ReadRequest
-	11, // 12: fsdriver.FileSystemService.Close:input_type -> fsdriver.CloseRequest
-	13, // 13: fsdriver.FileSystemService.Watch:input_type -> fsdriver.WatchRequest
-	4,  // 14: fsdriver.FileSystemService.Stat:output_type -> fsdriver.StatResponse
-	6,  // 15: fsdriver.FileSystemService.ReadDir:output_type -> fsdriver.ReadDirResponse
-	8,  // 16: fsdriver.FileSystemService.Open:output_type -> fsdriver.OpenResponse
-	10, // 17: fsdriver.FileSystemService.Read:output_type -> fsdriver.ReadResponse
-	12, // 18: fsdriver.FileSystemService.Close:output_type -> fsdriver.CloseResponse
-	14, // 19: fsdriver.FileSystemService.Watch:output_type -> fsdriver.WatchEvent
-	14, // [14:20] is the sub-list for method output_type
-	8,  // [8:14] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	4,  // 0: fsdriver.StatResponse.info:type_name -> fsdriver.FileInfo
+	5,  // 1: fsdriver.StatResponse.error:type_name -> fsdriver.Error
+	4,  // 2: fsdriver.ReadDirResponse.entries:type_name -> fsdriver.FileInfo
+	5,  // 3: fsdriver.ReadDirResponse.error:type_name -> fsdriver.Error
+	5,  // 4: fsdriver.OpenResponse.error:type_name -> fsdriver.Error
+	5,  // 5: fsdriver.ReadResponse.error:type_name -> fsdriver.Error
+	5,  // 6: fsdriver.ReadChunk.error:type_name -> fsdriver.Error
+	5,  // 7: fsdriver.CloseResponse.error:type_name -> fsdriver.Error
+	4,  // 8: fsdriver.CreateResponse.info:type_name -> fsdriver.FileInfo
+	5,  // 9: fsdriver.CreateResponse.error:type_name -> fsdriver.Error
+	5,  // 10: fsdriver.WriteResponse.error:type_name -> fsdriver.Error
+	5,  // 11: fsdriver.TruncateResponse.error:type_name -> fsdriver.Error
+	5,  // 12: fsdriver.ChtimesResponse.error:type_name -> fsdriver.Error
+	4,  // 13: fsdriver.MkdirResponse.info:type_name -> fsdriver.FileInfo
+	5,  // 14: fsdriver.MkdirResponse.error:type_name -> fsdriver.Error
+	5,  // 15: fsdriver.RmdirResponse.error:type_name -> fsdriver.Error
+	5,  // 16: fsdriver.UnlinkResponse.error:type_name -> fsdriver.Error
+	5,  // 17: fsdriver.RenameResponse.error:type_name -> fsdriver.Error
+	5,  // 18: fsdriver.ReadlinkResponse.error:type_name -> fsdriver.Error
+	4,  // 19: fsdriver.SymlinkResponse.info:type_name -> fsdriver.FileInfo
+	5,  // 20: fsdriver.SymlinkResponse.error:type_name -> fsdriver.Error
+	39, // 21: fsdriver.StatfsResponse.stats:type_name -> fsdriver.FsStats
+	5,  // 22: fsdriver.StatfsResponse.error:type_name -> fsdriver.Error
+	0,  // 23: fsdriver.WatchRequest.op:type_name -> fsdriver.WatchOperation
+	1,  // 24: fsdriver.WatchRequest.event_types:type_name -> fsdriver.WatchEventType
+	1,  // 25: fsdriver.WatchEvent.type:type_name -> fsdriver.WatchEventType
+	43, // 26: fsdriver.WatchEvent.ack:type_name -> fsdriver.WatchAck
+	44, // 27: fsdriver.WatchEvent.error:type_name -> fsdriver.WatchError
+	45, // 28: fsdriver.WatchEvent.overflow:type_name -> fsdriver.WatchOverflow
+	46, // 29: fsdriver.WatchEvent.heartbeat:type_name -> fsdriver.WatchHeartbeat
+	5,  // 30: fsdriver.WatchError.error:type_name -> fsdriver.Error
+	2,  // 31: fsdriver.FileSystemService.GetCapabilities:input_type -> fsdriver.CapabilitiesRequest
+	6,  // 32: fsdriver.FileSystemService.Stat:input_type -> fsdriver.StatRequest
+	8,  // 33: fsdriver.FileSystemService.ReadDir:input_type -> fsdriver.ReadDirRequest
+	10, // 34: fsdriver.FileSystemService.Open:input_type -> fsdriver.OpenRequest
+	12, // 35: fsdriver.FileSystemService.Read:input_type -> fsdriver.ReadRequest
+	14, // 36: fsdriver.FileSystemService.ReadStream:input_type -> fsdriver.ReadStreamRequest
+	16, // 37: fsdriver.FileSystemService.Close:input_type -> fsdriver.CloseRequest
+	18, // 38: fsdriver.FileSystemService.Create:input_type -> fsdriver.CreateRequest
+	20, // 39: fsdriver.FileSystemService.Write:input_type -> fsdriver.WriteRequest
+	22, // 40: fsdriver.FileSystemService.Truncate:input_type -> fsdriver.TruncateRequest
+	24, // 41: fsdriver.FileSystemService.Chtimes:input_type -> fsdriver.ChtimesRequest
+	26, // 42: fsdriver.FileSystemService.Mkdir:input_type -> fsdriver.MkdirRequest
+	28, // 43: fsdriver.FileSystemService.Rmdir:input_type -> fsdriver.RmdirRequest
+	30, // 44: fsdriver.FileSystemService.Unlink:input_type -> fsdriver.UnlinkRequest
+	32, // 45: fsdriver.FileSystemService.Rename:input_type -> fsdriver.RenameRequest
+	34, // 46: fsdriver.FileSystemService.Readlink:input_type -> fsdriver.ReadlinkRequest
+	36, // 47: fsdriver.FileSystemService.Symlink:input_type -> fsdriver.SymlinkRequest
+	38, // 48: fsdriver.FileSystemService.Statfs:input_type -> fsdriver.StatfsRequest
+	41, // 49: fsdriver.FileSystemService.Watch:input_type -> fsdriver.WatchRequest
+	3,  // 50: fsdriver.FileSystemService.GetCapabilities:output_type -> fsdriver.CapabilitiesResponse
+	7,  // 51: fsdriver.FileSystemService.Stat:output_type -> fsdriver.StatResponse
+	9,  // 52: fsdriver.FileSystemService.ReadDir:output_type -> fsdriver.ReadDirResponse
+	11, // 53: fsdriver.FileSystemService.Open:output_type -> fsdriver.OpenResponse
+	13, // 54: fsdriver.FileSystemService.Read:output_type -> fsdriver.ReadResponse
+	15, // 55: fsdriver.FileSystemService.ReadStream:output_type -> fsdriver.ReadChunk
+	17, // 56: fsdriver.FileSystemService.Close:output_type -> fsdriver.CloseResponse
+	19, // 57: fsdriver.FileSystemService.Create:output_type -> fsdriver.CreateResponse
+	21, // 58: fsdriver.FileSystemService.Write:output_type -> fsdriver.WriteResponse
+	23, // 59: fsdriver.FileSystemService.Truncate:output_type -> fsdriver.TruncateResponse
+	25, // 60: fsdriver.FileSystemService.Chtimes:output_type -> fsdriver.ChtimesResponse
+	27, // 61: fsdriver.FileSystemService.Mkdir:output_type -> fsdriver.MkdirResponse
+	29, // 62: fsdriver.FileSystemService.Rmdir:output_type -> fsdriver.RmdirResponse
+	31, // 63: fsdriver.FileSystemService.Unlink:output_type -> fsdriver.UnlinkResponse
+	33, // 64: fsdriver.FileSystemService.Rename:output_type -> fsdriver.RenameResponse
+	35, // 65: fsdriver.FileSystemService.Readlink:output_type -> fsdriver.ReadlinkResponse
+	37, // 66: fsdriver.FileSystemService.Symlink:output_type -> fsdriver.SymlinkResponse
+	40, // 67: fsdriver.FileSystemService.Statfs:output_type -> fsdriver.StatfsResponse
+	42, // 68: fsdriver.FileSystemService.Watch:output_type -> fsdriver.WatchEvent
+	50, // [50:69] is the sub-list for method output_type
+	31, // [31:50] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_proto_fsdriver_proto_init() }
@@ -1145,25 +3560,55 @@ func file_proto_fsdriver_proto_init() {
 	if File_proto_fsdriver_proto != nil {
 		return
 	}
-	file_proto_fsdriver_proto_msgTypes[3].OneofWrappers = []any{
+	file_proto_fsdriver_proto_msgTypes[5].OneofWrappers = []any{
 		(*StatResponse_Info)(nil),
 		(*StatResponse_Error)(nil),
 	}
-	file_proto_fsdriver_proto_msgTypes[7].OneofWrappers = []any{
+	file_proto_fsdriver_proto_msgTypes[9].OneofWrappers = []any{
 		(*OpenResponse_Handle)(nil),
 		(*OpenResponse_Error)(nil),
 	}
-	file_proto_fsdriver_proto_msgTypes[9].OneofWrappers = []any{
+	file_proto_fsdriver_proto_msgTypes[11].OneofWrappers = []any{
 		(*ReadResponse_Data)(nil),
 		(*ReadResponse_Error)(nil),
 	}
+	file_proto_fsdriver_proto_msgTypes[13].OneofWrappers = []any{
+		(*ReadChunk_Data)(nil),
+		(*ReadChunk_Error)(nil),
+	}
+	file_proto_fsdriver_proto_msgTypes[19].OneofWrappers = []any{
+		(*WriteResponse_Written)(nil),
+		(*WriteResponse_Error)(nil),
+	}
+	file_proto_fsdriver_proto_msgTypes[25].OneofWrappers = []any{
+		(*MkdirResponse_Info)(nil),
+		(*MkdirResponse_Error)(nil),
+	}
+	file_proto_fsdriver_proto_msgTypes[33].OneofWrappers = []any{
+		(*ReadlinkResponse_Target)(nil),
+		(*ReadlinkResponse_Error)(nil),
+	}
+	file_proto_fsdriver_proto_msgTypes[35].OneofWrappers = []any{
+		(*SymlinkResponse_Info)(nil),
+		(*SymlinkResponse_Error)(nil),
+	}
+	file_proto_fsdriver_proto_msgTypes[38].OneofWrappers = []any{
+		(*StatfsResponse_Stats)(nil),
+		(*StatfsResponse_Error)(nil),
+	}
+	file_proto_fsdriver_proto_msgTypes[40].OneofWrappers = []any{
+		(*WatchEvent_Ack)(nil),
+		(*WatchEvent_Error)(nil),
+		(*WatchEvent_Overflow)(nil),
+		(*WatchEvent_Heartbeat)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_fsdriver_proto_rawDesc), len(file_proto_fsdriver_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   14,
+			NumEnums:      2,
+			NumMessages:   45,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,30 +19,69 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileSystemService_Stat_FullMethodName    = "/fsdriver.FileSystemService/Stat"
-	FileSystemService_ReadDir_FullMethodName = "/fsdriver.FileSystemService/ReadDir"
-	FileSystemService_Open_FullMethodName    = "/fsdriver.FileSystemService/Open"
-	FileSystemService_Read_FullMethodName    = "/fsdriver.FileSystemService/Read"
-	FileSystemService_Close_FullMethodName   = "/fsdriver.FileSystemService/Close"
-	FileSystemService_Watch_FullMethodName   = "/fsdriver.FileSystemService/Watch"
+	FileSystemService_GetCapabilities_FullMethodName = "/fsdriver.FileSystemService/GetCapabilities"
+	FileSystemService_Stat_FullMethodName            = "/fsdriver.FileSystemService/Stat"
+	FileSystemService_ReadDir_FullMethodName         = "/fsdriver.FileSystemService/ReadDir"
+	FileSystemService_Open_FullMethodName            = "/fsdriver.FileSystemService/Open"
+	FileSystemService_Read_FullMethodName            = "/fsdriver.FileSystemService/Read"
+	FileSystemService_ReadStream_FullMethodName      = "/fsdriver.FileSystemService/ReadStream"
+	FileSystemService_Close_FullMethodName           = "/fsdriver.FileSystemService/Close"
+	FileSystemService_Create_FullMethodName          = "/fsdriver.FileSystemService/Create"
+	FileSystemService_Write_FullMethodName           = "/fsdriver.FileSystemService/Write"
+	FileSystemService_Truncate_FullMethodName        = "/fsdriver.FileSystemService/Truncate"
+	FileSystemService_Chtimes_FullMethodName         = "/fsdriver.FileSystemService/Chtimes"
+	FileSystemService_Mkdir_FullMethodName           = "/fsdriver.FileSystemService/Mkdir"
+	FileSystemService_Rmdir_FullMethodName           = "/fsdriver.FileSystemService/Rmdir"
+	FileSystemService_Unlink_FullMethodName          = "/fsdriver.FileSystemService/Unlink"
+	FileSystemService_Rename_FullMethodName          = "/fsdriver.FileSystemService/Rename"
+	FileSystemService_Readlink_FullMethodName        = "/fsdriver.FileSystemService/Readlink"
+	FileSystemService_Symlink_FullMethodName         = "/fsdriver.FileSystemService/Symlink"
+	FileSystemService_Statfs_FullMethodName          = "/fsdriver.FileSystemService/Statfs"
+	FileSystemService_Watch_FullMethodName           = "/fsdriver.FileSystemService/Watch"
 )
 
 // FileSystemServiceClient is the client API for FileSystemService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
-// File system service for read-only operations
+// File system service
 type FileSystemServiceClient interface {
+	// Negotiate limits; called by the client after connecting
+	GetCapabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
 	// Get file/directory attributes
 	Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error)
-	// List directory contents
+	// List directory contents, one page per call
 	ReadDir(ctx context.Context, in *ReadDirRequest, opts ...grpc.CallOption) (*ReadDirResponse, error)
 	// Open a file for reading
 	Open(ctx context.Context, in *OpenRequest, opts ...grpc.CallOption) (*OpenResponse, error)
 	// Read data from an open file
 	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// Stream a range of an open file in chunks (sequential reads)
+	ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadChunk], error)
 	// Close an open file
 	Close(ctx context.Context, in *CloseRequest, opts ...grpc.CallOption) (*CloseResponse, error)
+	// Create a file and open it
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	// Write data to an open file
+	Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error)
+	// Change the size of a file
+	Truncate(ctx context.Context, in *TruncateRequest, opts ...grpc.CallOption) (*TruncateResponse, error)
+	// Change the access and modification time of a file
+	Chtimes(ctx context.Context, in *ChtimesRequest, opts ...grpc.CallOption) (*ChtimesResponse, error)
+	// Create a directory
+	Mkdir(ctx context.Context, in *MkdirRequest, opts ...grpc.CallOption) (*MkdirResponse, error)
+	// Remove an empty directory
+	Rmdir(ctx context.Context, in *RmdirRequest, opts ...grpc.CallOption) (*RmdirResponse, error)
+	// Remove a file
+	Unlink(ctx context.Context, in *UnlinkRequest, opts ...grpc.CallOption) (*UnlinkResponse, error)
+	// Rename or move a file or directory
+	Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*RenameResponse, error)
+	// Read the target of a symbolic link
+	Readlink(ctx context.Context, in *ReadlinkRequest, opts ...grpc.CallOption) (*ReadlinkResponse, error)
+	// Create a symbolic link
+	Symlink(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (*SymlinkResponse, error)
+	// Report capacity and usage of the share's volume
+	Statfs(ctx context.Context, in *StatfsRequest, opts ...grpc.CallOption) (*StatfsResponse, error)
 	// Watch for changes (bidirectional stream)
 	Watch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[WatchRequest, WatchEvent], error)
 }
@@ -55,6 +94,16 @@ func NewFileSystemServiceClient(cc grpc.ClientConnInterface) FileSystemServiceCl
 	return &fileSystemServiceClient{cc}
 }
 
+func (c *fileSystemServiceClient) GetCapabilities(ctx context.Context, in *CapabilitiesRequest, opts ...grpc.CallOption) (*CapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CapabilitiesResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileSystemServiceClient) Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StatResponse)
@@ -95,6 +144,25 @@ func (c *fileSystemServiceClient) Read(ctx context.Context, in *ReadRequest, opt
 	return out, nil
 }
 
+func (c *fileSystemServiceClient) ReadStream(ctx context.Context, in *ReadStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileSystemService_ServiceDesc.Streams[0], FileSystemService_ReadStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadStreamRequest, ReadChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileSystemService_ReadStreamClient = grpc.ServerStreamingClient[ReadChunk]
+
 func (c *fileSystemServiceClient) Close(ctx context.Context, in *CloseRequest, opts ...grpc.CallOption) (*CloseResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CloseResponse)
@@ -105,9 +173,119 @@ func (c *fileSystemServiceClient) Close(ctx context.Context, in *CloseRequest, o
 	return out, nil
 }
 
+func (c *fileSystemServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Write(ctx context.Context, in *WriteRequest, opts ...grpc.CallOption) (*WriteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WriteResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Write_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Truncate(ctx context.Context, in *TruncateRequest, opts ...grpc.CallOption) (*TruncateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TruncateResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Truncate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Chtimes(ctx context.Context, in *ChtimesRequest, opts ...grpc.CallOption) (*ChtimesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChtimesResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Chtimes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Mkdir(ctx context.Context, in *MkdirRequest, opts ...grpc.CallOption) (*MkdirResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MkdirResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Mkdir_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Rmdir(ctx context.Context, in *RmdirRequest, opts ...grpc.CallOption) (*RmdirResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RmdirResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Rmdir_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Unlink(ctx context.Context, in *UnlinkRequest, opts ...grpc.CallOption) (*UnlinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnlinkResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Unlink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*RenameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Rename_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Readlink(ctx context.Context, in *ReadlinkRequest, opts ...grpc.CallOption) (*ReadlinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadlinkResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Readlink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Symlink(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (*SymlinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SymlinkResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Symlink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) Statfs(ctx context.Context, in *StatfsRequest, opts ...grpc.CallOption) (*StatfsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatfsResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_Statfs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileSystemServiceClient) Watch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[WatchRequest, WatchEvent], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &FileSystemService_ServiceDesc.Streams[0], FileSystemService_Watch_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &FileSystemService_ServiceDesc.Streams[1], FileSystemService_Watch_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -122,18 +300,44 @@ type FileSystemService_WatchClient = grpc.BidiStreamingClient[WatchRequest, Watc
 // All implementations must embed UnimplementedFileSystemServiceServer
 // for forward compatibility.
 //
-// File system service for read-only operations
+// File system service
 type FileSystemServiceServer interface {
+	// Negotiate limits; called by the client after connecting
+	GetCapabilities(context.Context, *CapabilitiesRequest) (*CapabilitiesResponse, error)
 	// Get file/directory attributes
 	Stat(context.Context, *StatRequest) (*StatResponse, error)
-	// List directory contents
+	// List directory contents, one page per call
 	ReadDir(context.Context, *ReadDirRequest) (*ReadDirResponse, error)
 	// Open a file for reading
 	Open(context.Context, *OpenRequest) (*OpenResponse, error)
 	// Read data from an open file
 	Read(context.Context, *ReadRequest) (*ReadResponse, error)
+	// Stream a range of an open file in chunks (sequential reads)
+	ReadStream(*ReadStreamRequest, grpc.ServerStreamingServer[ReadChunk]) error
 	// Close an open file
 	Close(context.Context, *CloseRequest) (*CloseResponse, error)
+	// Create a file and open it
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	// Write data to an open file
+	Write(context.Context, *WriteRequest) (*WriteResponse, error)
+	// Change the size of a file
+	Truncate(context.Context, *TruncateRequest) (*TruncateResponse, error)
+	// Change the access and modification time of a file
+	Chtimes(context.Context, *ChtimesRequest) (*ChtimesResponse, error)
+	// Create a directory
+	Mkdir(context.Context, *MkdirRequest) (*MkdirResponse, error)
+	// Remove an empty directory
+	Rmdir(context.Context, *RmdirRequest) (*RmdirResponse, error)
+	// Remove a file
+	Unlink(context.Context, *UnlinkRequest) (*UnlinkResponse, error)
+	// Rename or move a file or directory
+	Rename(context.Context, *RenameRequest) (*RenameResponse, error)
+	// Read the target of a symbolic link
+	Readlink(context.Context, *ReadlinkRequest) (*ReadlinkResponse, error)
+	// Create a symbolic link
+	Symlink(context.Context, *SymlinkRequest) (*SymlinkResponse, error)
+	// Report capacity and usage of the share's volume
+	Statfs(context.Context, *StatfsRequest) (*StatfsResponse, error)
 	// Watch for changes (bidirectional stream)
 	Watch(grpc.BidiStreamingServer[WatchRequest, WatchEvent]) error
 	mustEmbedUnimplementedFileSystemServiceServer()
@@ -146,6 +350,9 @@ type FileSystemServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedFileSystemServiceServer struct{}
 
+func (UnimplementedFileSystemServiceServer) GetCapabilities(context.Context, *CapabilitiesRequest) (*CapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
 func (UnimplementedFileSystemServiceServer) Stat(context.Context, *StatRequest) (*StatResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stat not implemented")
 }
@@ -158,9 +365,45 @@ func (UnimplementedFileSystemServiceServer) Open(context.Context, *OpenRequest)
 func (UnimplementedFileSystemServiceServer) Read(context.Context, *ReadRequest) (*ReadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (UnimplementedFileSystemServiceServer) ReadStream(*ReadStreamRequest, grpc.ServerStreamingServer[ReadChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadStream not implemented")
+}
 func (UnimplementedFileSystemServiceServer) Close(context.Context, *CloseRequest) (*CloseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Close not implemented")
 }
+func (UnimplementedFileSystemServiceServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Write(context.Context, *WriteRequest) (*WriteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Write not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Truncate(context.Context, *TruncateRequest) (*TruncateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Truncate not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Chtimes(context.Context, *ChtimesRequest) (*ChtimesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Chtimes not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Mkdir(context.Context, *MkdirRequest) (*MkdirResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Mkdir not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Rmdir(context.Context, *RmdirRequest) (*RmdirResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rmdir not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Unlink(context.Context, *UnlinkRequest) (*UnlinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlink not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Rename(context.Context, *RenameRequest) (*RenameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rename not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Readlink(context.Context, *ReadlinkRequest) (*ReadlinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Readlink not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Symlink(context.Context, *SymlinkRequest) (*SymlinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Symlink not implemented")
+}
+func (UnimplementedFileSystemServiceServer) Statfs(context.Context, *StatfsRequest) (*StatfsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Statfs not implemented")
+}
 func (UnimplementedFileSystemServiceServer) Watch(grpc.BidiStreamingServer[WatchRequest, WatchEvent]) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
@@ -185,6 +428,24 @@ func RegisterFileSystemServiceServer(s grpc.ServiceRegistrar, srv FileSystemServ
 	s.RegisterService(&FileSystemService_ServiceDesc, srv)
 }
 
+func _FileSystemService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).GetCapabilities(ctx, req.(*CapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileSystemService_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StatRequest)
 	if err := dec(in); err != nil {
@@ -257,6 +518,17 @@ func _FileSystemService_Read_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileSystemService_ReadStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileSystemServiceServer).ReadStream(m, &grpc.GenericServerStream[ReadStreamRequest, ReadChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileSystemService_ReadStreamServer = grpc.ServerStreamingServer[ReadChunk]
+
 func _FileSystemService_Close_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CloseRequest)
 	if err := dec(in); err != nil {
@@ -275,6 +547,204 @@ func _FileSystemService_Close_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileSystemService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Write_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Write(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Write_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Write(ctx, req.(*WriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Truncate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TruncateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Truncate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Truncate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Truncate(ctx, req.(*TruncateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Chtimes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChtimesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Chtimes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Chtimes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Chtimes(ctx, req.(*ChtimesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Mkdir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MkdirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Mkdir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Mkdir_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Mkdir(ctx, req.(*MkdirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Rmdir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RmdirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Rmdir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Rmdir_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Rmdir(ctx, req.(*RmdirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Unlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Unlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Unlink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Unlink(ctx, req.(*UnlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Rename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Rename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Rename_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Rename(ctx, req.(*RenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Readlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Readlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Readlink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Readlink(ctx, req.(*ReadlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Symlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SymlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Symlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Symlink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Symlink(ctx, req.(*SymlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_Statfs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatfsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).Statfs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_Statfs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).Statfs(ctx, req.(*StatfsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileSystemService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(FileSystemServiceServer).Watch(&grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
 }
@@ -289,6 +759,10 @@ var FileSystemService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "fsdriver.FileSystemService",
 	HandlerType: (*FileSystemServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _FileSystemService_GetCapabilities_Handler,
+		},
 		{
 			MethodName: "Stat",
 			Handler:    _FileSystemService_Stat_Handler,
@@ -309,8 +783,57 @@ var FileSystemService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Close",
 			Handler:    _FileSystemService_Close_Handler,
 		},
+		{
+			MethodName: "Create",
+			Handler:    _FileSystemService_Create_Handler,
+		},
+		{
+			MethodName: "Write",
+			Handler:    _FileSystemService_Write_Handler,
+		},
+		{
+			MethodName: "Truncate",
+			Handler:    _FileSystemService_Truncate_Handler,
+		},
+		{
+			MethodName: "Chtimes",
+			Handler:    _FileSystemService_Chtimes_Handler,
+		},
+		{
+			MethodName: "Mkdir",
+			Handler:    _FileSystemService_Mkdir_Handler,
+		},
+		{
+			MethodName: "Rmdir",
+			Handler:    _FileSystemService_Rmdir_Handler,
+		},
+		{
+			MethodName: "Unlink",
+			Handler:    _FileSystemService_Unlink_Handler,
+		},
+		{
+			MethodName: "Rename",
+			Handler:    _FileSystemService_Rename_Handler,
+		},
+		{
+			MethodName: "Readlink",
+			Handler:    _FileSystemService_Readlink_Handler,
+		},
+		{
+			MethodName: "Symlink",
+			Handler:    _FileSystemService_Symlink_Handler,
+		},
+		{
+			MethodName: "Statfs",
+			Handler:    _FileSystemService_Statfs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadStream",
+			Handler:       _FileSystemService_ReadStream_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Watch",
 			Handler:       _FileSystemService_Watch_Handler,
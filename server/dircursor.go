@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+)
+
+// Directory listing pages and cursors.
+const (
+	defaultDirPage = 1024
+	maxDirPage     = 4096
+	// maxCursorsPerSession bounds abandoned listings; the least recently
+	// used cursor of the session is dropped to make room.
+	maxCursorsPerSession = 64
+	// cursorIdleTimeout closes listings a client stopped paging through.
+	cursorIdleTimeout = 5 * time.Minute
+)
+
+// dirCursor is an open directory in the middle of a paginated listing. Pages
+// are read from the same directory handle, so each entry is returned once
+// even if the directory changes between pages.
+type dirCursor struct {
+	token    string
+	session  uint64
+	absPath  string
+	dir      *os.File
+	lastUsed time.Time
+}
+
+// storeCursor registers c for the caller's session and returns its token.
+func (s *fileSystemServer) storeCursor(ctx context.Context, c *dirCursor) string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err) // crypto/rand does not fail on supported platforms
+	}
+	c.token = hex.EncodeToString(b[:])
+	c.session = sessionFromContext(ctx)
+	c.lastUsed = time.Now()
+
+	s.mu.Lock()
+	var oldest *dirCursor
+	owned := 0
+	for _, other := range s.cursors {
+		if other.session != c.session {
+			continue
+		}
+		owned++
+		if oldest == nil || other.lastUsed.Before(oldest.lastUsed) {
+			oldest = other
+		}
+	}
+	if owned >= maxCursorsPerSession {
+		delete(s.cursors, oldest.token)
+	} else {
+		oldest = nil
+	}
+	s.cursors[c.token] = c
+	s.mu.Unlock()
+
+	if oldest != nil {
+		logx.Info("dropping least recently used directory cursor", "session", c.session, "path", oldest.absPath)
+		_ = oldest.dir.Close()
+	}
+	return c.token
+}
+
+// takeCursor removes and returns the cursor if it belongs to the caller's
+// session; the caller stores it again if the listing continues.
+func (s *fileSystemServer) takeCursor(ctx context.Context, token string) *dirCursor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.cursors[token]
+	if c == nil || c.session != sessionFromContext(ctx) {
+		return nil
+	}
+	delete(s.cursors, token)
+	return c
+}
+
+// closeSessionCursors closes every cursor owned by session.
+func (s *fileSystemServer) closeSessionCursors(session uint64) {
+	s.mu.Lock()
+	var closing []*dirCursor
+	for token, c := range s.cursors {
+		if c.session == session {
+			closing = append(closing, c)
+			delete(s.cursors, token)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range closing {
+		_ = c.dir.Close()
+	}
+}
+
+// reapIdleCursors closes cursors unused for cursorIdleTimeout until ctx is
+// cancelled.
+func (s *fileSystemServer) reapIdleCursors(ctx context.Context) {
+	ticker := time.NewTicker(cursorIdleTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.closeIdleCursors(now.Add(-cursorIdleTimeout))
+		}
+	}
+}
+
+// closeIdleCursors closes every cursor last used before cutoff.
+func (s *fileSystemServer) closeIdleCursors(cutoff time.Time) {
+	s.mu.Lock()
+	var idle []*dirCursor
+	for token, c := range s.cursors {
+		if c.lastUsed.Before(cutoff) {
+			idle = append(idle, c)
+			delete(s.cursors, token)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range idle {
+		logx.Info("closing idle directory cursor", "session", c.session, "path", c.absPath)
+		_ = c.dir.Close()
+	}
+}
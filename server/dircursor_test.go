@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// cursorShare serves a share whose directory "d" needs several pages of two.
+func cursorShare(t *testing.T) *fileSystemServer {
+	t.Helper()
+	s := newTestServer(t, serverOptions{})
+	mkdirs(t, s, "d")
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(filepath.Join(s.root, "d", fmt.Sprintf("f%d", i)), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// readDir lists one page of two entries of "d" and returns the wire errno,
+// 0 for success, with the next cursor.
+func readDir(t *testing.T, s *fileSystemServer, ctx context.Context, cursor string) (int32, string) {
+	t.Helper()
+	resp, err := s.ReadDir(ctx, &pb.ReadDirRequest{Path: "d", Limit: 2, Cursor: cursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.GetError().GetCode(), resp.NextCursor
+}
+
+func TestCursorBelongsToItsSession(t *testing.T) {
+	s := cursorShare(t)
+	mine, other := sessionContext(1), sessionContext(2)
+	_, cursor := readDir(t, s, mine, "")
+	if cursor == "" {
+		t.Fatal("no cursor for a partial listing")
+	}
+	if code, _ := readDir(t, s, other, cursor); code != codeEINVAL {
+		t.Errorf("continuing another session's listing: errno %d, want EINVAL", code)
+	}
+	if code, _ := readDir(t, s, mine, cursor); code != 0 {
+		t.Errorf("continuing after a foreign attempt: errno %d", code)
+	}
+}
+
+func TestIdleCursorsExpire(t *testing.T) {
+	s := cursorShare(t)
+	ctx := sessionContext(1)
+	_, stale := readDir(t, s, ctx, "")
+	_, fresh := readDir(t, s, ctx, "")
+	s.mu.Lock()
+	dir := s.cursors[stale].dir
+	s.cursors[stale].lastUsed = time.Now().Add(-cursorIdleTimeout - time.Second)
+	s.mu.Unlock()
+
+	s.closeIdleCursors(time.Now().Add(-cursorIdleTimeout))
+	if code, _ := readDir(t, s, ctx, stale); code != codeEINVAL {
+		t.Errorf("continuing an expired listing: errno %d, want EINVAL", code)
+	}
+	checkClosed(t, dir)
+	if code, _ := readDir(t, s, ctx, fresh); code != 0 {
+		t.Errorf("continuing a recent listing: errno %d", code)
+	}
+}
+
+func TestCursorsPerSessionAreCapped(t *testing.T) {
+	s := cursorShare(t)
+	ctx := sessionContext(1)
+	_, other := readDir(t, s, sessionContext(2), "")
+	var cursors []string
+	for i := 0; i <= maxCursorsPerSession; i++ {
+		_, c := readDir(t, s, ctx, "")
+		cursors = append(cursors, c)
+		// Distinct ages, so the first is the least recently used
+		s.mu.Lock()
+		s.cursors[c].lastUsed = time.Now().Add(time.Duration(i-maxCursorsPerSession) * time.Second)
+		s.mu.Unlock()
+	}
+	if code, _ := readDir(t, s, ctx, cursors[0]); code != codeEINVAL {
+		t.Errorf("least recently used cursor beyond the cap: errno %d, want EINVAL", code)
+	}
+	for _, c := range []string{cursors[1], cursors[maxCursorsPerSession]} {
+		if code, _ := readDir(t, s, ctx, c); code != 0 {
+			t.Errorf("cursor within the cap: errno %d", code)
+		}
+	}
+	if code, _ := readDir(t, s, sessionContext(2), other); code != 0 {
+		t.Errorf("another session's cursor: errno %d", code)
+	}
+}
+
+func TestReleaseCursor(t *testing.T) {
+	s := cursorShare(t)
+	ctx := sessionContext(1)
+	_, cursor := readDir(t, s, ctx, "")
+	s.mu.Lock()
+	dir := s.cursors[cursor].dir
+	s.mu.Unlock()
+
+	release := &pb.ReadDirRequest{Path: "d", Cursor: cursor, Release: true}
+	if resp, _ := s.ReadDir(sessionContext(2), release); resp.GetError().GetCode() != codeEINVAL {
+		t.Errorf("releasing another session's cursor: %v, want EINVAL", resp.GetError())
+	}
+	resp, err := s.ReadDir(ctx, release)
+	if err != nil || resp.Error != nil || len(resp.Entries) != 0 {
+		t.Fatalf("release: %v, %v, %d entries", resp.GetError(), err, len(resp.Entries))
+	}
+	checkClosed(t, dir)
+	if code, _ := readDir(t, s, ctx, cursor); code != codeEINVAL {
+		t.Errorf("continuing a released listing: errno %d, want EINVAL", code)
+	}
+}
+
+func TestReadDirRejectsLegacyOffset(t *testing.T) {
+	s := cursorShare(t)
+	resp, err := s.ReadDir(context.Background(), &pb.ReadDirRequest{Path: "d", Offset: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetError().GetCode() != codeEINVAL || len(resp.Entries) != 0 {
+		t.Errorf("offset without a cursor: %v with %d entries, want EINVAL", resp.GetError(), len(resp.Entries))
+	}
+}
@@ -1,21 +1,151 @@
 package main
 
 import (
-    "errors"
-    "os"
+	"errors"
+	"os"
+	"syscall"
 
-    pb "github.com/example/fsdriver/proto"
+	pb "github.com/example/fsdriver/proto"
 )
 
-// errno maps generic errors to POSIX-like codes; extend with Win32 specifics later.
+// POSIX errno values sent in pb.Error.Code. They use the Linux numbering the
+// FUSE client expects, regardless of the platform the server runs on.
+const (
+	codeEPERM        int32 = 1
+	codeENOENT       int32 = 2
+	codeEINTR        int32 = 4
+	codeEIO          int32 = 5
+	codeEBADF        int32 = 9
+	codeEAGAIN       int32 = 11
+	codeENOMEM       int32 = 12
+	codeEACCES       int32 = 13
+	codeEBUSY        int32 = 16
+	codeEEXIST       int32 = 17
+	codeEXDEV        int32 = 18
+	codeENOTDIR      int32 = 20
+	codeEISDIR       int32 = 21
+	codeEINVAL       int32 = 22
+	codeENFILE       int32 = 23
+	codeEMFILE       int32 = 24
+	codeEFBIG        int32 = 27
+	codeENOSPC       int32 = 28
+	codeESPIPE       int32 = 29
+	codeEROFS        int32 = 30
+	codeEMLINK       int32 = 31
+	codeEPIPE        int32 = 32
+	codeENAMETOOLONG int32 = 36
+	codeENOSYS       int32 = 38
+	codeENOTEMPTY    int32 = 39
+	codeELOOP        int32 = 40
+	codeENOTSUP      int32 = 95
+	codeETIMEDOUT    int32 = 110
+	codeEDQUOT       int32 = 122
+)
+
+// posixError is a failure detected by the server itself that maps directly to
+// a wire errno.
+type posixError struct {
+	code    int32
+	message string
+}
+
+func (e *posixError) Error() string { return e.message }
+
+var (
+	errUnknownHandle  = &posixError{codeEBADF, "unknown handle"}
+	errTooManyHandles = &posixError{codeEMFILE, "too many open handles"}
+	errInvalidOffset  = &posixError{codeEINVAL, "invalid offset/size"}
+	errInvalidSize    = &posixError{codeEINVAL, "invalid size"}
+	errInvalidTime    = &posixError{codeEINVAL, "invalid time"}
+	errInvalidFlags   = &posixError{codeEINVAL, "invalid flags"}
+	errInvalidCursor  = &posixError{codeEINVAL, "unknown or expired directory cursor"}
+	errLegacyOffset   = &posixError{codeEINVAL, "ReadDir offsets are not supported, page with cursor"}
+	errInvalidGlob    = &posixError{codeEINVAL, "invalid glob pattern"}
+	errUnknownSub     = &posixError{codeEINVAL, "unknown watch subscription"}
+	errReadOnly       = &posixError{codeEROFS, "read-only share"}
+	errEscapesRoot    = &posixError{codeEACCES, "path escapes root"}
+	errTooManyLinks   = &posixError{codeELOOP, "too many levels of symbolic links"}
+	errShareRoot      = &posixError{codeEBUSY, "operation not allowed on share root"}
+	errNotDir         = &posixError{codeENOTDIR, "not a directory"}
+	errIsDir          = &posixError{codeEISDIR, "is a directory"}
+	errExist          = &posixError{codeEEXIST, "file exists"}
+	errAbsoluteTarget = &posixError{codeEPERM, "absolute symlink targets are not supported"}
+	errEmptyTarget    = &posixError{codeENOENT, "empty symlink target"}
+)
+
+// errnoCodes maps host syscall errors to wire errnos. On Windows these are the
+// errno values invented by the Go runtime; Win32 codes are handled separately.
+var errnoCodes = map[syscall.Errno]int32{
+	syscall.EPERM:        codeEPERM,
+	syscall.ENOENT:       codeENOENT,
+	syscall.EINTR:        codeEINTR,
+	syscall.EIO:          codeEIO,
+	syscall.EBADF:        codeEBADF,
+	syscall.EAGAIN:       codeEAGAIN,
+	syscall.ENOMEM:       codeENOMEM,
+	syscall.EACCES:       codeEACCES,
+	syscall.EBUSY:        codeEBUSY,
+	syscall.EEXIST:       codeEEXIST,
+	syscall.EXDEV:        codeEXDEV,
+	syscall.ENOTDIR:      codeENOTDIR,
+	syscall.EISDIR:       codeEISDIR,
+	syscall.EINVAL:       codeEINVAL,
+	syscall.ENFILE:       codeENFILE,
+	syscall.EMFILE:       codeEMFILE,
+	syscall.EFBIG:        codeEFBIG,
+	syscall.ENOSPC:       codeENOSPC,
+	syscall.ESPIPE:       codeESPIPE,
+	syscall.EROFS:        codeEROFS,
+	syscall.EMLINK:       codeEMLINK,
+	syscall.EPIPE:        codeEPIPE,
+	syscall.ENAMETOOLONG: codeENAMETOOLONG,
+	syscall.ENOSYS:       codeENOSYS,
+	syscall.ENOTEMPTY:    codeENOTEMPTY,
+	syscall.ELOOP:        codeELOOP,
+	syscall.ENOTSUP:      codeENOTSUP,
+	syscall.ETIMEDOUT:    codeETIMEDOUT,
+	syscall.EDQUOT:       codeEDQUOT,
+}
+
+// errno maps an error to its wire representation. The Win32 code is filled in
+// whenever the error carries one.
 func errno(err error) *pb.Error {
-    if errors.Is(err, os.ErrNotExist) {
-        return &pb.Error{Code: 2, Message: err.Error()} // ENOENT
-    }
-    if errors.Is(err, os.ErrPermission) {
-        return &pb.Error{Code: 13, Message: err.Error()} // EACCES
-    }
-    return &pb.Error{Code: 5, Message: err.Error()} // EIO default
+	code, win32 := classify(err)
+	return &pb.Error{
+		Code:          code,
+		Message:       err.Error(),
+		Win32Code:     win32,
+		UnknownHandle: errors.Is(err, errUnknownHandle),
+	}
 }
 
+// classify returns the wire errno and, if known, the Win32 code for err.
+func classify(err error) (code int32, win32 int32) {
+	var pe *posixError
+	if errors.As(err, &pe) {
+		return pe.code, 0
+	}
 
+	var en syscall.Errno
+	if errors.As(err, &en) {
+		if code, win32, ok := platformErrno(en); ok {
+			return code, win32
+		}
+	}
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return codeENOENT, 0
+	case errors.Is(err, os.ErrPermission):
+		return codeEACCES, 0
+	case errors.Is(err, os.ErrExist):
+		return codeEEXIST, 0
+	case errors.Is(err, os.ErrClosed):
+		return codeEBADF, 0
+	case errors.Is(err, os.ErrInvalid):
+		return codeEINVAL, 0
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return codeETIMEDOUT, 0
+	}
+	return codeEIO, 0
+}
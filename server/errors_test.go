@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	_, notFound := os.Open(filepath.Join(t.TempDir(), "missing"))
+	tests := []struct {
+		name string
+		err  error
+		code int32
+	}{
+		{"server error", errUnknownHandle, codeEBADF},
+		{"wrapped server error", fmt.Errorf("open: %w", errEscapesRoot), codeEACCES},
+		{"link loop", errTooManyLinks, codeELOOP},
+		{"read-only share", errReadOnly, codeEROFS},
+		{"absolute link target", errAbsoluteTarget, codeEPERM},
+		{"host errno", &os.PathError{Op: "rmdir", Path: "d", Err: syscall.ENOTEMPTY}, codeENOTEMPTY},
+		{"host errno in link error", &os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.EXDEV}, codeEXDEV},
+		{"failed open", notFound, codeENOENT},
+		{"not exist", os.ErrNotExist, codeENOENT},
+		{"permission", os.ErrPermission, codeEACCES},
+		{"exist", os.ErrExist, codeEEXIST},
+		{"closed", os.ErrClosed, codeEBADF},
+		{"invalid", os.ErrInvalid, codeEINVAL},
+		{"deadline", os.ErrDeadlineExceeded, codeETIMEDOUT},
+		{"anything else", errors.New("boom"), codeEIO},
+	}
+	for _, tt := range tests {
+		if code, _ := classify(tt.err); code != tt.code {
+			t.Errorf("%s: errno %d, want %d", tt.name, code, tt.code)
+		}
+	}
+}
+
+func TestErrnoKeepsMessage(t *testing.T) {
+	e := errno(errInvalidCursor)
+	if e.Code != codeEINVAL || e.Message != errInvalidCursor.Error() || e.Win32Code != 0 {
+		t.Errorf("errno(errInvalidCursor) = %v", e)
+	}
+}
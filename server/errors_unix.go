@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import (
+	"syscall"
+)
+
+// platformErrno classifies a host errno; there is no Win32 code off Windows.
+func platformErrno(en syscall.Errno) (code int32, win32 int32, ok bool) {
+	code, ok = errnoCodes[en]
+	return code, 0, ok
+}
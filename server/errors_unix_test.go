@@ -0,0 +1,25 @@
+//go:build !windows
+
+package main
+
+import (
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func TestErrnoCatalog(t *testing.T) {
+	for en, want := range errnoCodes {
+		code, win32 := classify(en)
+		if code != want || win32 != 0 {
+			t.Errorf("%v: classified as %d (win32 %d), want %d", en, code, win32, want)
+		}
+		// The wire uses Linux numbering, so on Linux it is the identity
+		if runtime.GOOS == "linux" && int32(en) != want {
+			t.Errorf("%v is %d on Linux but sent as %d", en, int32(en), want)
+		}
+	}
+	if code, _ := classify(syscall.Errno(0xffff)); code != codeEIO {
+		t.Errorf("unknown errno: %d, want EIO", code)
+	}
+}
@@ -0,0 +1,65 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+)
+
+// win32Codes maps Win32 error codes (GetLastError) to wire errnos.
+var win32Codes = map[syscall.Errno]int32{
+	1:    codeENOSYS,       // ERROR_INVALID_FUNCTION
+	2:    codeENOENT,       // ERROR_FILE_NOT_FOUND
+	3:    codeENOENT,       // ERROR_PATH_NOT_FOUND
+	4:    codeEMFILE,       // ERROR_TOO_MANY_OPEN_FILES
+	5:    codeEACCES,       // ERROR_ACCESS_DENIED
+	6:    codeEBADF,        // ERROR_INVALID_HANDLE
+	8:    codeENOMEM,       // ERROR_NOT_ENOUGH_MEMORY
+	14:   codeENOMEM,       // ERROR_OUTOFMEMORY
+	15:   codeENOENT,       // ERROR_INVALID_DRIVE
+	16:   codeEBUSY,        // ERROR_CURRENT_DIRECTORY
+	17:   codeEXDEV,        // ERROR_NOT_SAME_DEVICE
+	19:   codeEROFS,        // ERROR_WRITE_PROTECT
+	32:   codeEBUSY,        // ERROR_SHARING_VIOLATION
+	33:   codeEBUSY,        // ERROR_LOCK_VIOLATION
+	39:   codeENOSPC,       // ERROR_HANDLE_DISK_FULL
+	50:   codeENOTSUP,      // ERROR_NOT_SUPPORTED
+	53:   codeENOENT,       // ERROR_BAD_NETPATH
+	80:   codeEEXIST,       // ERROR_FILE_EXISTS
+	82:   codeEACCES,       // ERROR_CANNOT_MAKE
+	87:   codeEINVAL,       // ERROR_INVALID_PARAMETER
+	109:  codeEPIPE,        // ERROR_BROKEN_PIPE
+	112:  codeENOSPC,       // ERROR_DISK_FULL
+	120:  codeENOSYS,       // ERROR_CALL_NOT_IMPLEMENTED
+	123:  codeEINVAL,       // ERROR_INVALID_NAME
+	131:  codeEINVAL,       // ERROR_NEGATIVE_SEEK
+	145:  codeENOTEMPTY,    // ERROR_DIR_NOT_EMPTY
+	161:  codeENOENT,       // ERROR_BAD_PATHNAME
+	170:  codeEBUSY,        // ERROR_BUSY
+	183:  codeEEXIST,       // ERROR_ALREADY_EXISTS
+	206:  codeENAMETOOLONG, // ERROR_FILENAME_EXCED_RANGE
+	223:  codeEFBIG,        // ERROR_FILE_TOO_LARGE
+	267:  codeENOTDIR,      // ERROR_DIRECTORY
+	681:  codeELOOP,        // ERROR_STOPPED_ON_SYMLINK
+	1295: codeEDQUOT,       // ERROR_DISK_QUOTA_EXCEEDED
+	1314: codeEPERM,        // ERROR_PRIVILEGE_NOT_HELD
+	1921: codeELOOP,        // ERROR_CANT_RESOLVE_FILENAME
+	4390: codeEINVAL,       // ERROR_NOT_A_REPARSE_POINT
+}
+
+// platformErrno classifies a syscall.Errno. On Windows it is either a Win32
+// code from the OS or one of the errno values the Go runtime invents above
+// APPLICATION_ERROR.
+func platformErrno(en syscall.Errno) (code int32, win32 int32, ok bool) {
+	if code, ok := win32Codes[en]; ok {
+		return code, int32(en), true
+	}
+	if code, ok := errnoCodes[en]; ok {
+		return code, 0, true
+	}
+	if en < syscall.APPLICATION_ERROR {
+		// Unlisted Win32 code: keep it for diagnostics.
+		return codeEIO, int32(en), true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestWin32Catalog(t *testing.T) {
+	for en, want := range win32Codes {
+		code, win32 := classify(en)
+		if code != want || win32 != int32(en) {
+			t.Errorf("Win32 %d: classified as %d (win32 %d), want %d", en, code, win32, want)
+		}
+	}
+}
+
+func TestWin32Codes(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		code  int32
+		win32 int32
+	}{
+		{"file not found", syscall.ERROR_FILE_NOT_FOUND, codeENOENT, 2},
+		{"access denied", syscall.ERROR_ACCESS_DENIED, codeEACCES, 5},
+		{"privilege not held", syscall.Errno(1314), codeEPERM, 1314},
+		{"not a reparse point", syscall.Errno(4390), codeEINVAL, 4390},
+		{"unlisted Win32 code", syscall.Errno(9999), codeEIO, 9999},
+		// Invented by the Go runtime, above APPLICATION_ERROR
+		{"runtime errno", syscall.ENOTEMPTY, codeENOTEMPTY, 0},
+	}
+	for _, tt := range tests {
+		code, win32 := classify(tt.err)
+		if code != tt.code || win32 != tt.win32 {
+			t.Errorf("%s: got %d (win32 %d), want %d (win32 %d)", tt.name, code, win32, tt.code, tt.win32)
+		}
+	}
+}
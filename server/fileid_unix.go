@@ -0,0 +1,26 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the device, inode number and link count of an entry.
+func fileID(absPath string, fi os.FileInfo) (dev, ino uint64, nlink uint32) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, 1
+	}
+	return uint64(st.Dev), uint64(st.Ino), uint32(st.Nlink)
+}
+
+// fileMeta returns the metadata of an entry beyond os.FileInfo. Stat has
+// already filled in all of it.
+func fileMeta(absPath string, fi os.FileInfo) fileMetadata {
+	var m fileMetadata
+	m.dev, m.ino, m.nlink = fileID(absPath, fi)
+	m.atime, m.ctime, m.btime = fileTimes(absPath, fi)
+	return m
+}
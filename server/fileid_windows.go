@@ -0,0 +1,60 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the volume serial number, NTFS file index and link count of
+// an entry. os.FileInfo does not expose them, so they are queried from a
+// handle.
+func fileID(absPath string, fi os.FileInfo) (dev, ino uint64, nlink uint32) {
+	h, err := openMetadata(absPath)
+	if err != nil {
+		return 0, 0, 1
+	}
+	defer syscall.CloseHandle(h)
+	return handleID(h)
+}
+
+// fileMeta returns the metadata of an entry beyond os.FileInfo. The file ID
+// and change time are queried from a single handle; if it cannot be opened
+// the attribute data Go keeps is all there is.
+func fileMeta(absPath string, fi os.FileInfo) fileMetadata {
+	m := fileMetadata{nlink: 1}
+	m.atime, m.ctime, m.btime = attrTimes(fi)
+	h, err := openMetadata(absPath)
+	if err != nil {
+		return m
+	}
+	defer syscall.CloseHandle(h)
+	m.dev, m.ino, m.nlink = handleID(h)
+	if ctime, ok := changeTime(h); ok {
+		m.ctime = ctime
+	}
+	return m
+}
+
+func handleID(h syscall.Handle) (dev, ino uint64, nlink uint32) {
+	var d syscall.ByHandleFileInformation
+	if err := syscall.GetFileInformationByHandle(h, &d); err != nil {
+		return 0, 0, 1
+	}
+	return uint64(d.VolumeSerialNumber), uint64(d.FileIndexHigh)<<32 | uint64(d.FileIndexLow), d.NumberOfLinks
+}
+
+// openMetadata opens an entry for querying its metadata only: without data
+// access, so it works on directories and files opened elsewhere, and without
+// following reparse points, so a link reports on itself like Lstat.
+func openMetadata(absPath string) (syscall.Handle, error) {
+	p, err := syscall.UTF16PtrFromString(absPath)
+	if err != nil {
+		return 0, err
+	}
+	return syscall.CreateFile(p, 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING,
+		syscall.FILE_FLAG_BACKUP_SEMANTICS|syscall.FILE_FLAG_OPEN_REPARSE_POINT, 0)
+}
@@ -0,0 +1,46 @@
+package main
+
+import "sync"
+
+// maxIndexedFiles bounds the file index; when it is full it starts over.
+const maxIndexedFiles = 1 << 16
+
+// fileKey identifies a file independently of its name.
+type fileKey struct {
+	dev, ino uint64
+}
+
+// fileIndex remembers the file IDs last seen at host paths: those reported
+// to clients and those of entries created while watched. Once a file is
+// renamed its old name can no longer be stat'ed, so this is what rename
+// pairing compares the new name against.
+type fileIndex struct {
+	mu  sync.Mutex
+	ids map[string]fileKey
+}
+
+func newFileIndex() *fileIndex {
+	return &fileIndex{ids: make(map[string]fileKey)}
+}
+
+func (x *fileIndex) record(abs string, id fileKey) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.ids[abs]; !ok && len(x.ids) >= maxIndexedFiles {
+		clear(x.ids)
+	}
+	x.ids[abs] = id
+}
+
+func (x *fileIndex) lookup(abs string) (fileKey, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	id, ok := x.ids[abs]
+	return id, ok
+}
+
+func (x *fileIndex) forget(abs string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	delete(x.ids, abs)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// fileOps performs the file system operations behind requests on host paths
+// returned by confine. Unless escaping links are allowed, an implementation
+// should keep a link swapped in after confine from redirecting them.
+type fileOps interface {
+	openFile(abs string, flag int, perm os.FileMode) (*os.File, error)
+	lstat(abs string) (os.FileInfo, error)
+	mkdir(abs string, perm os.FileMode) error
+	remove(abs string, dir bool) error
+	rename(oldAbs, newAbs string) error
+	symlink(target, abs string) error
+	readlink(abs string) (string, error)
+	// chtimes leaves a zero time unchanged.
+	chtimes(abs string, atime, mtime time.Time) error
+}
+
+// pathOps acts on paths as given. Only opened files are checked, after the
+// fact; a swapped link still redirects the other operations, and the side
+// effects of opening, such as creating or truncating the file.
+type pathOps struct {
+	s *fileSystemServer
+}
+
+func (o pathOps) openFile(abs string, flag int, perm os.FileMode) (*os.File, error) {
+	f, err := os.OpenFile(abs, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.s.checkOpened(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (o pathOps) lstat(abs string) (os.FileInfo, error) {
+	return os.Lstat(abs)
+}
+
+func (o pathOps) mkdir(abs string, perm os.FileMode) error {
+	return os.Mkdir(abs, perm)
+}
+
+func (o pathOps) remove(abs string, dir bool) error {
+	return os.Remove(abs)
+}
+
+func (o pathOps) rename(oldAbs, newAbs string) error {
+	return os.Rename(oldAbs, newAbs)
+}
+
+func (o pathOps) symlink(target, abs string) error {
+	return os.Symlink(target, abs)
+}
+
+func (o pathOps) readlink(abs string) (string, error) {
+	return os.Readlink(abs)
+}
+
+func (o pathOps) chtimes(abs string, atime, mtime time.Time) error {
+	return os.Chtimes(abs, atime, mtime)
+}
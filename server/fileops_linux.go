@@ -0,0 +1,203 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// newFileOps returns the operations for s: resolved below the share root
+// unless escaping links are allowed or the kernel lacks openat2 (before 5.6).
+func newFileOps(s *fileSystemServer) fileOps {
+	if s.opts.allowSymlinkEscape {
+		return pathOps{s}
+	}
+	if !haveOpenat2() {
+		logx.Error("openat2 not available; a link swapped in after a path check can lead out of the share")
+		return pathOps{s}
+	}
+	return beneathOps{root: s.root}
+}
+
+var haveOpenat2 = sync.OnceValue(func() bool {
+	fd, err := unix.Openat2(unix.AT_FDCWD, "/", &unix.OpenHow{Flags: unix.O_PATH | unix.O_CLOEXEC})
+	if err != nil {
+		return false
+	}
+	_ = unix.Close(fd)
+	return true
+})
+
+// beneathOps has the kernel resolve every path again, below the share root
+// and without following any link. confine has already resolved the links a
+// path may pass, so a link met now was swapped in after the check; the
+// operation then fails with ELOOP before it has any effect. Operations on
+// entries act through a handle on their parent directory.
+type beneathOps struct {
+	root string
+}
+
+const resolveBeneath = unix.RESOLVE_BENEATH | unix.RESOLVE_NO_SYMLINKS | unix.RESOLVE_NO_MAGICLINKS
+
+// open opens abs, the root or a path below it, relative to the root.
+func (o beneathOps) open(abs string, flags int, perm os.FileMode) (int, error) {
+	rel, err := filepath.Rel(o.root, abs)
+	if err != nil || startsWithDotDot(rel) {
+		return -1, errEscapesRoot
+	}
+	root, err := unix.Open(o.root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: o.root, Err: err}
+	}
+	defer unix.Close(root)
+	how := &unix.OpenHow{Flags: uint64(flags | unix.O_CLOEXEC), Resolve: resolveBeneath}
+	if flags&unix.O_CREAT != 0 {
+		// openat2 rejects a mode without O_CREAT
+		how.Mode = uint64(perm.Perm())
+	}
+	for {
+		fd, err := unix.Openat2(root, rel, how)
+		// EAGAIN: a concurrent rename got in the way of the lookup
+		if err == unix.EINTR || err == unix.EAGAIN {
+			continue
+		}
+		if err != nil {
+			return -1, &os.PathError{Op: "open", Path: abs, Err: err}
+		}
+		return fd, nil
+	}
+}
+
+// parent opens the directory holding abs and returns the name of abs in it.
+func (o beneathOps) parent(abs string) (fd int, name string, err error) {
+	if abs == o.root {
+		fd, err = o.open(abs, unix.O_PATH|unix.O_DIRECTORY, 0)
+		return fd, ".", err
+	}
+	fd, err = o.open(filepath.Dir(abs), unix.O_PATH|unix.O_DIRECTORY, 0)
+	return fd, filepath.Base(abs), err
+}
+
+func (o beneathOps) openFile(abs string, flag int, perm os.FileMode) (*os.File, error) {
+	fd, err := o.open(abs, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), abs), nil
+}
+
+func (o beneathOps) lstat(abs string) (os.FileInfo, error) {
+	// With O_PATH|O_NOFOLLOW a final link is opened rather than refused
+	fd, err := o.open(abs, unix.O_PATH|unix.O_NOFOLLOW, 0)
+	if pe, ok := err.(*os.PathError); ok {
+		pe.Op = "lstat"
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), abs)
+	defer f.Close()
+	return f.Stat()
+}
+
+func (o beneathOps) mkdir(abs string, perm os.FileMode) error {
+	dir, name, err := o.parent(abs)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dir)
+	if err := unix.Mkdirat(dir, name, uint32(perm.Perm())); err != nil {
+		return &os.PathError{Op: "mkdir", Path: abs, Err: err}
+	}
+	return nil
+}
+
+func (o beneathOps) remove(abs string, isDir bool) error {
+	dir, name, err := o.parent(abs)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dir)
+	flags := 0
+	if isDir {
+		flags = unix.AT_REMOVEDIR
+	}
+	if err := unix.Unlinkat(dir, name, flags); err != nil {
+		return &os.PathError{Op: "remove", Path: abs, Err: err}
+	}
+	return nil
+}
+
+func (o beneathOps) rename(oldAbs, newAbs string) error {
+	oldDir, oldName, err := o.parent(oldAbs)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(oldDir)
+	newDir, newName, err := o.parent(newAbs)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(newDir)
+	if err := unix.Renameat(oldDir, oldName, newDir, newName); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldAbs, New: newAbs, Err: err}
+	}
+	return nil
+}
+
+func (o beneathOps) symlink(target, abs string) error {
+	dir, name, err := o.parent(abs)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dir)
+	if err := unix.Symlinkat(target, dir, name); err != nil {
+		return &os.LinkError{Op: "symlink", Old: target, New: abs, Err: err}
+	}
+	return nil
+}
+
+func (o beneathOps) readlink(abs string) (string, error) {
+	dir, name, err := o.parent(abs)
+	if err != nil {
+		return "", err
+	}
+	defer unix.Close(dir)
+	for size := 128; ; size *= 2 {
+		buf := make([]byte, size)
+		n, err := unix.Readlinkat(dir, name, buf)
+		if err != nil {
+			return "", &os.PathError{Op: "readlink", Path: abs, Err: err}
+		}
+		if n < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
+
+// chtimes does not follow a final link: confine has already resolved the
+// one a request may follow.
+func (o beneathOps) chtimes(abs string, atime, mtime time.Time) error {
+	dir, name, err := o.parent(abs)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dir)
+	ts := []unix.Timespec{timespec(atime), timespec(mtime)}
+	if err := unix.UtimesNanoAt(dir, name, ts, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return &os.PathError{Op: "chtimes", Path: abs, Err: err}
+	}
+	return nil
+}
+
+func timespec(t time.Time) unix.Timespec {
+	if t.IsZero() {
+		return unix.Timespec{Nsec: unix.UTIME_OMIT}
+	}
+	return unix.NsecToTimespec(t.UnixNano())
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBeneathRefusesSwappedLinks(t *testing.T) {
+	s, outside := linkShare(t, serverOptions{})
+	ops, ok := s.ops.(beneathOps)
+	if !ok {
+		t.Skip("openat2 not available")
+	}
+	if err := os.WriteFile(s.hostPath("victim"), []byte("victim"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Paths as confine resolved them, before sub and victim are swapped for
+	// links out of the share
+	dir := s.hostPath("sub")
+	victim := s.hostPath("victim")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(victim); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret"), victim); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(dir, "secret")
+	old := time.Unix(1000000000, 0)
+
+	tests := []struct {
+		name string
+		op   func() error
+	}{
+		{"open", func() error { _, err := ops.openFile(secret, os.O_RDONLY, 0); return err }},
+		{"create", func() error {
+			_, err := ops.openFile(filepath.Join(dir, "new"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+			return err
+		}},
+		{"truncate final", func() error { _, err := ops.openFile(victim, os.O_WRONLY|os.O_TRUNC, 0); return err }},
+		{"lstat", func() error { _, err := ops.lstat(secret); return err }},
+		{"mkdir", func() error { return ops.mkdir(filepath.Join(dir, "new"), 0o755) }},
+		{"remove", func() error { return ops.remove(secret, false) }},
+		{"rename", func() error { return ops.rename(secret, s.hostPath("stolen")) }},
+		{"symlink", func() error { return ops.symlink("x", filepath.Join(dir, "new")) }},
+		{"readlink", func() error { _, err := ops.readlink(secret); return err }},
+		{"chtimes", func() error { return ops.chtimes(secret, old, old) }},
+	}
+	for _, tt := range tests {
+		if err := tt.op(); !errors.Is(err, syscall.ELOOP) {
+			t.Errorf("%s through a swapped link: error %v, want ELOOP", tt.name, err)
+		}
+	}
+
+	// A final link is acted on itself, never its target
+	if err := ops.chtimes(victim, old, old); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filepath.Join(outside, "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size() != int64(len("secret")) || fi.ModTime().Equal(old) {
+		t.Errorf("secret modified through the share: size %d, mtime %v", fi.Size(), fi.ModTime())
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "new")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("entry created outside the share: %v", err)
+	}
+}
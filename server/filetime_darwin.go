@@ -0,0 +1,18 @@
+//go:build darwin
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the access, status change and birth time of an entry.
+func fileTimes(absPath string, fi os.FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fi.ModTime(), fi.ModTime(), time.Time{}
+	}
+	return time.Unix(st.Atimespec.Unix()), time.Unix(st.Ctimespec.Unix()), time.Unix(st.Birthtimespec.Unix())
+}
@@ -0,0 +1,19 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the access, status change and birth time of an entry.
+// stat(2) carries no birth time, so it is left zero.
+func fileTimes(absPath string, fi os.FileInfo) (atime, ctime, btime time.Time) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fi.ModTime(), fi.ModTime(), time.Time{}
+	}
+	return time.Unix(st.Atim.Unix()), time.Unix(st.Ctim.Unix()), time.Time{}
+}
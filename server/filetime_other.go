@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// fileTimes falls back to the modification time where the platform's stat
+// data is not decoded.
+func fileTimes(absPath string, fi os.FileInfo) (atime, ctime, btime time.Time) {
+	return fi.ModTime(), fi.ModTime(), time.Time{}
+}
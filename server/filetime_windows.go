@@ -0,0 +1,47 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileBasicInfo is FILE_BASIC_INFO; times are FILETIMEs.
+type fileBasicInfo struct {
+	CreationTime   int64
+	LastAccessTime int64
+	LastWriteTime  int64
+	ChangeTime     int64
+	FileAttributes uint32
+	_              uint32
+}
+
+// attrTimes returns the access and birth time of an entry from the
+// attribute data Go keeps. That has no NTFS change time, so the last write
+// time stands in for it until changeTime supplies the real one.
+func attrTimes(fi os.FileInfo) (atime, ctime, btime time.Time) {
+	d, ok := fi.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return fi.ModTime(), fi.ModTime(), time.Time{}
+	}
+	atime = time.Unix(0, d.LastAccessTime.Nanoseconds())
+	btime = time.Unix(0, d.CreationTime.Nanoseconds())
+	return atime, fi.ModTime(), btime
+}
+
+// changeTime returns the NTFS change time of the entry open as h.
+func changeTime(h syscall.Handle) (time.Time, bool) {
+	var info fileBasicInfo
+	err := windows.GetFileInformationByHandleEx(windows.Handle(h), windows.FileBasicInfo,
+		(*byte)(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)))
+	if err != nil || info.ChangeTime == 0 {
+		return time.Time{}, false
+	}
+	ft := syscall.Filetime{LowDateTime: uint32(info.ChangeTime), HighDateTime: uint32(info.ChangeTime >> 32)}
+	return time.Unix(0, ft.Nanoseconds()), true
+}
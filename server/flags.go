@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+)
+
+// Open flags on the wire use the Linux open(2) values the FUSE client
+// receives from the kernel; translate them to the host's os.O_* values.
+const (
+	wireACCMODE int32 = 0x3
+	wireRDONLY  int32 = 0x0
+	wireWRONLY  int32 = 0x1
+	wireRDWR    int32 = 0x2
+	wireCREAT   int32 = 0x40
+	wireEXCL    int32 = 0x80
+	wireTRUNC   int32 = 0x200
+	wireAPPEND  int32 = 0x400
+)
+
+// openFlags converts wire open flags to flags for os.OpenFile. Bits without a
+// portable equivalent (O_LARGEFILE, O_NOFOLLOW, ...) are dropped.
+func openFlags(wire int32) int {
+	var flags int
+	switch wire & wireACCMODE {
+	case wireWRONLY:
+		flags = os.O_WRONLY
+	case wireRDWR:
+		flags = os.O_RDWR
+	default:
+		flags = os.O_RDONLY
+	}
+	if wire&wireCREAT != 0 {
+		flags |= os.O_CREATE
+	}
+	if wire&wireEXCL != 0 {
+		flags |= os.O_EXCL
+	}
+	if wire&wireTRUNC != 0 {
+		flags |= os.O_TRUNC
+	}
+	if wire&wireAPPEND != 0 {
+		flags |= os.O_APPEND
+	}
+	return flags
+}
+
+// isWriteOpen reports whether wire open flags request write access or would
+// modify the file.
+func isWriteOpen(wire int32) bool {
+	if wire&wireACCMODE != wireRDONLY {
+		return true
+	}
+	return wire&(wireCREAT|wireTRUNC|wireAPPEND) != 0
+}
@@ -1,38 +1,159 @@
 package main
 
 import (
-    "os"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"os"
+	"sync/atomic"
+	"time"
 )
 
 type fileHandle struct {
-    id      int32
-    absPath string
-    file    *os.File
+	id       int32
+	session  uint64 // Owning client session
+	absPath  string
+	file     *os.File
+	flags    int          // os.OpenFile flags the file was opened with
+	lastUsed atomic.Int64 // Unix nanoseconds of the last request using the handle
 }
 
-func (s *fileSystemServer) registerHandle(absPath string, f *os.File) int32 {
-    s.mu.Lock()
-    defer s.mu.Unlock()
-    s.nextHandleID++
-    id := s.nextHandleID
-    s.handles[id] = &fileHandle{id: id, absPath: absPath, file: f}
-    return id
+func (h *fileHandle) touch() {
+	h.lastUsed.Store(time.Now().UnixNano())
 }
 
-func (s *fileSystemServer) getHandle(id int32) *fileHandle {
-    s.mu.Lock()
-    defer s.mu.Unlock()
-    return s.handles[id]
+// registerHandle stores an open file under a random, unused handle ID owned
+// by the caller's session. It fails with EMFILE when the session is at its
+// handle limit; the caller still owns f in that case.
+func (s *fileSystemServer) registerHandle(ctx context.Context, absPath string, f *os.File, flags int) (int32, error) {
+	session := sessionFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owned := s.sessions[session]
+	if s.opts.maxHandlesPerSession > 0 && len(owned) >= s.opts.maxHandlesPerSession {
+		return 0, errTooManyHandles
+	}
+	id := s.newHandleIDLocked()
+	h := &fileHandle{id: id, session: session, absPath: absPath, file: f, flags: flags}
+	h.touch()
+	s.handles[id] = h
+	if owned == nil {
+		owned = make(map[int32]struct{})
+		s.sessions[session] = owned
+	}
+	owned[id] = struct{}{}
+	return id, nil
+}
+
+// newHandleIDLocked picks a random positive handle ID so clients cannot guess
+// each other's handles. The caller must hold s.mu.
+func (s *fileSystemServer) newHandleIDLocked() int32 {
+	var b [4]byte
+	for {
+		if _, err := rand.Read(b[:]); err != nil {
+			panic(err) // crypto/rand does not fail on supported platforms
+		}
+		id := int32(binary.LittleEndian.Uint32(b[:]) & 0x7fffffff)
+		if id == 0 {
+			continue
+		}
+		if _, taken := s.handles[id]; !taken {
+			return id
+		}
+	}
+}
+
+// getHandle returns the handle if it exists and belongs to the caller's session.
+func (s *fileSystemServer) getHandle(ctx context.Context, id int32) *fileHandle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.handles[id]
+	if h == nil || h.session != sessionFromContext(ctx) {
+		return nil
+	}
+	h.touch()
+	return h
+}
+
+// takeHandle removes and returns the handle if it belongs to the caller's session.
+func (s *fileSystemServer) takeHandle(ctx context.Context, id int32) *fileHandle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.handles[id]
+	if h == nil || h.session != sessionFromContext(ctx) {
+		return nil
+	}
+	s.removeHandleLocked(h)
+	return h
 }
 
-func (s *fileSystemServer) takeHandle(id int32) *fileHandle {
-    s.mu.Lock()
-    defer s.mu.Unlock()
-    h := s.handles[id]
-    if h != nil {
-        delete(s.handles, id)
-    }
-    return h
+func (s *fileSystemServer) removeHandleLocked(h *fileHandle) {
+	delete(s.handles, h.id)
+	if owned := s.sessions[h.session]; owned != nil {
+		delete(owned, h.id)
+		if len(owned) == 0 {
+			delete(s.sessions, h.session)
+		}
+	}
 }
 
+// closeSession closes every handle and directory cursor owned by session and
+// reports how many handles were closed.
+func (s *fileSystemServer) closeSession(session uint64) int {
+	s.closeSessionCursors(session)
 
+	s.mu.Lock()
+	var closing []*fileHandle
+	for id := range s.sessions[session] {
+		closing = append(closing, s.handles[id])
+	}
+	for _, h := range closing {
+		s.removeHandleLocked(h)
+	}
+	s.mu.Unlock()
+
+	for _, h := range closing {
+		_ = h.file.Close()
+	}
+	return len(closing)
+}
+
+// reapIdleHandles closes handles unused for longer than the idle timeout,
+// checking at a fraction of it. It returns when ctx is cancelled.
+func (s *fileSystemServer) reapIdleHandles(ctx context.Context) {
+	timeout := s.opts.handleIdleTimeout
+	if timeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(timeout/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.closeIdleHandles(now.Add(-timeout))
+		}
+	}
+}
+
+// closeIdleHandles closes every handle last used before cutoff.
+func (s *fileSystemServer) closeIdleHandles(cutoff time.Time) {
+	s.mu.Lock()
+	var idle []*fileHandle
+	for _, h := range s.handles {
+		if h.lastUsed.Load() < cutoff.UnixNano() {
+			idle = append(idle, h)
+		}
+	}
+	for _, h := range idle {
+		s.removeHandleLocked(h)
+	}
+	s.mu.Unlock()
+
+	for _, h := range idle {
+		logx.Info("closing idle handle", "handle", h.id, "session", h.session, "path", h.absPath)
+		_ = h.file.Close()
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/stats"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+func sessionContext(id uint64) context.Context {
+	return context.WithValue(context.Background(), sessionKey{}, id)
+}
+
+// openIn opens name, creating it if needed, on behalf of the session in ctx.
+func openIn(t *testing.T, s *fileSystemServer, ctx context.Context, name string) (int32, *pb.Error) {
+	t.Helper()
+	p := filepath.Join(s.root, name)
+	if _, err := os.Stat(p); err != nil {
+		if err := os.WriteFile(p, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resp, err := s.Open(ctx, &pb.OpenRequest{Path: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.GetHandle(), resp.GetError()
+}
+
+// readCode reads from handle and returns the wire errno, 0 for success.
+func readCode(t *testing.T, s *fileSystemServer, ctx context.Context, handle int32) int32 {
+	t.Helper()
+	resp, err := s.Read(ctx, &pb.ReadRequest{Handle: handle, Size: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.GetError().GetCode()
+}
+
+// checkClosed fails unless the file behind a handle has been closed.
+func checkClosed(t *testing.T, f *os.File) {
+	t.Helper()
+	if _, err := f.Stat(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("%s left open", f.Name())
+	}
+}
+
+func TestHandlesBelongToTheirSession(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	mine, other := sessionContext(1), sessionContext(2)
+	h, e := openIn(t, s, mine, "f")
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	rresp, err := s.Read(other, &pb.ReadRequest{Handle: h, Size: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := rresp.GetError(); e.GetCode() != codeEBADF || !e.GetUnknownHandle() {
+		t.Errorf("Read from another session: %v, want an unknown handle", e)
+	}
+	resp, err := s.Close(other, &pb.CloseRequest{Handle: h})
+	if err != nil || resp.GetError().GetCode() != codeEBADF {
+		t.Errorf("Close from another session: %v, %v; want EBADF", resp.GetError(), err)
+	}
+	if code := readCode(t, s, mine, h); code != 0 {
+		t.Errorf("Read by the owner after a foreign Close: errno %d", code)
+	}
+	if resp, _ := s.Close(mine, &pb.CloseRequest{Handle: h}); resp.GetError() != nil {
+		t.Errorf("Close by the owner: %v", resp.GetError())
+	}
+}
+
+func TestIdleHandlesAreClosed(t *testing.T) {
+	s := newTestServer(t, serverOptions{handleIdleTimeout: time.Minute})
+	ctx := sessionContext(1)
+	idle, _ := openIn(t, s, ctx, "idle")
+	busy, _ := openIn(t, s, ctx, "busy")
+	idleFile := s.handles[idle].file
+	s.handles[idle].lastUsed.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	s.closeIdleHandles(time.Now().Add(-s.opts.handleIdleTimeout))
+	if code := readCode(t, s, ctx, idle); code != codeEBADF {
+		t.Errorf("Read of a reaped handle: errno %d, want EBADF", code)
+	}
+	checkClosed(t, idleFile)
+	if code := readCode(t, s, ctx, busy); code != 0 {
+		t.Errorf("Read of a recently used handle: errno %d", code)
+	}
+
+	// Using a handle keeps it from being reaped
+	s.handles[busy].lastUsed.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	readCode(t, s, ctx, busy)
+	s.closeIdleHandles(time.Now().Add(-s.opts.handleIdleTimeout))
+	if code := readCode(t, s, ctx, busy); code != 0 {
+		t.Errorf("Read of a handle used since: errno %d", code)
+	}
+}
+
+func TestMaxHandlesPerSession(t *testing.T) {
+	s := newTestServer(t, serverOptions{maxHandlesPerSession: 2})
+	ctx := sessionContext(1)
+	first, _ := openIn(t, s, ctx, "a")
+	if _, e := openIn(t, s, ctx, "b"); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := openIn(t, s, ctx, "c"); e.GetCode() != codeEMFILE {
+		t.Fatalf("Open beyond the limit: %v, want EMFILE", e)
+	}
+
+	// The limit is per session, and closing a handle frees a slot
+	if _, e := openIn(t, s, sessionContext(2), "c"); e != nil {
+		t.Errorf("Open in another session: %v", e)
+	}
+	if resp, _ := s.Close(ctx, &pb.CloseRequest{Handle: first}); resp.GetError() != nil {
+		t.Fatal(resp.GetError())
+	}
+	if _, e := openIn(t, s, ctx, "c"); e != nil {
+		t.Errorf("Open after a Close: %v", e)
+	}
+}
+
+func TestSessionEndClosesHandles(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	tracker := newSessionTracker(s)
+	ctx := tracker.TagConn(context.Background(), &stats.ConnTagInfo{})
+	other := tracker.TagConn(context.Background(), &stats.ConnTagInfo{})
+	var files []*os.File
+	for _, name := range []string{"a", "b"} {
+		h, e := openIn(t, s, ctx, name)
+		if e != nil {
+			t.Fatal(e)
+		}
+		files = append(files, s.handles[h].file)
+	}
+	kept, _ := openIn(t, s, other, "a")
+
+	tracker.HandleConn(ctx, &stats.ConnEnd{})
+	for _, f := range files {
+		checkClosed(t, f)
+	}
+	if len(s.handles) != 1 || len(s.sessions) != 1 {
+		t.Errorf("%d handles in %d sessions left, want only the other session's", len(s.handles), len(s.sessions))
+	}
+	if code := readCode(t, s, other, kept); code != 0 {
+		t.Errorf("Read in the remaining session: errno %d", code)
+	}
+}
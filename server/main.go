@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
@@ -82,9 +83,18 @@ func streamLoggingInterceptor(share string) grpc.StreamServerInterceptor {
 func main() {
 	var share string
 	var addr string
+	var opts serverOptions
+	var maxReadSize int
+	var symlinkEscape string
 
 	flag.StringVar(&share, "share", "", "Windows directory to share (root)")
 	flag.StringVar(&addr, "addr", "127.0.0.1:50051", "listen address")
+	flag.BoolVar(&opts.readOnly, "read-only", false, "export the share read-only (mutating requests fail with EROFS)")
+	flag.DurationVar(&opts.handleIdleTimeout, "handle-idle-timeout", 30*time.Minute, "close file handles unused for this long (0 disables)")
+	flag.IntVar(&opts.maxHandlesPerSession, "max-handles", 4096, "maximum open file handles per client connection (0 means unlimited)")
+	flag.IntVar(&maxReadSize, "max-read-size", 1<<20, "largest read in bytes served per request or stream chunk; larger reads return short")
+	flag.StringVar(&symlinkEscape, "symlink-escape", "reject", "links that resolve outside the share: reject (EACCES) or allow")
+	flag.DurationVar(&opts.watchDebounce, "watch-debounce", 100*time.Millisecond, "merge watch events for the same path within this window (0 disables)")
 	flag.Parse()
 
 	if share == "" {
@@ -92,6 +102,22 @@ func main() {
 		os.Exit(2)
 	}
 
+	// Bounded above to keep responses well under gRPC's default 4 MiB message limit
+	if maxReadSize < 4096 || maxReadSize > 2<<20 {
+		logx.Error("invalid flag value", "flag", "--max-read-size", "value", maxReadSize, "min", 4096, "max", 2<<20)
+		os.Exit(2)
+	}
+	opts.maxReadSize = int32(maxReadSize)
+
+	switch symlinkEscape {
+	case "reject":
+	case "allow":
+		opts.allowSymlinkEscape = true
+	default:
+		logx.Error("invalid flag value", "flag", "--symlink-escape", "value", symlinkEscape, "allowed", "reject|allow")
+		os.Exit(2)
+	}
+
 	// Validate share path exists and is a directory
 	info, err := os.Stat(share)
 	if err != nil {
@@ -125,18 +151,25 @@ func main() {
 		os.Exit(1)
 	}
 
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(loggingInterceptor(share)),
-		grpc.StreamInterceptor(streamLoggingInterceptor(share)),
-	)
-	srv, err := NewFileSystemServer(share)
+	srv, err := NewFileSystemServer(share, opts)
 	if err != nil {
 		logx.Error("failed to initialize server", "error", err)
 		os.Exit(1)
 	}
+	go srv.reapIdleHandles(context.Background())
+	go srv.reapIdleCursors(context.Background())
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(loggingInterceptor(share)),
+		grpc.StreamInterceptor(streamLoggingInterceptor(share)),
+		grpc.StatsHandler(newSessionTracker(srv)),
+	)
 	pb.RegisterFileSystemServiceServer(grpcServer, srv)
 
-	logx.Info("fsdriver server listening", "addr", addr, "share", share)
+	logx.Info("fsdriver server listening", "addr", addr, "share", share, "read_only", opts.readOnly,
+		"handle_idle_timeout", opts.handleIdleTimeout, "max_handles", opts.maxHandlesPerSession,
+		"max_read_size", opts.maxReadSize, "symlink_escape", symlinkEscape,
+		"watch_debounce", opts.watchDebounce)
 
 	// Show all available network interfaces
 	interfaces, err := net.Interfaces()
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// Rename flags on the wire use the Linux renameat2(2) values.
+const (
+	renameNoReplace uint32 = 0x1
+	renameExchange  uint32 = 0x2
+)
+
+func (s *fileSystemServer) Mkdir(ctx context.Context, req *pb.MkdirRequest) (*pb.MkdirResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.MkdirResponse{Result: &pb.MkdirResponse_Error{Error: e}}, nil
+	}
+	abs, err := s.confineLink(req.Path)
+	if err != nil {
+		return &pb.MkdirResponse{Result: &pb.MkdirResponse_Error{Error: errno(err)}}, nil
+	}
+	mode := os.FileMode(req.Mode).Perm()
+	if mode == 0 {
+		mode = 0o755
+	}
+	if err := s.ops.mkdir(abs, mode); err != nil {
+		return &pb.MkdirResponse{Result: &pb.MkdirResponse_Error{Error: errno(err)}}, nil
+	}
+	fi, err := s.ops.lstat(abs)
+	if err != nil {
+		return &pb.MkdirResponse{Result: &pb.MkdirResponse_Error{Error: errno(err)}}, nil
+	}
+	return &pb.MkdirResponse{Result: &pb.MkdirResponse_Info{Info: s.toFileInfo(abs, fi)}}, nil
+}
+
+func (s *fileSystemServer) Rmdir(ctx context.Context, req *pb.RmdirRequest) (*pb.RmdirResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.RmdirResponse{Error: e}, nil
+	}
+	abs, err := s.confineLink(req.Path)
+	if err != nil {
+		return &pb.RmdirResponse{Error: errno(err)}, nil
+	}
+	if abs == s.root {
+		return &pb.RmdirResponse{Error: errno(errShareRoot)}, nil
+	}
+	fi, err := s.ops.lstat(abs)
+	if err != nil {
+		return &pb.RmdirResponse{Error: errno(err)}, nil
+	}
+	if !fi.IsDir() {
+		return &pb.RmdirResponse{Error: errno(errNotDir)}, nil
+	}
+	if err := s.ops.remove(abs, true); err != nil {
+		return &pb.RmdirResponse{Error: errno(err)}, nil
+	}
+	return &pb.RmdirResponse{}, nil
+}
+
+func (s *fileSystemServer) Unlink(ctx context.Context, req *pb.UnlinkRequest) (*pb.UnlinkResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.UnlinkResponse{Error: e}, nil
+	}
+	abs, err := s.confineLink(req.Path)
+	if err != nil {
+		return &pb.UnlinkResponse{Error: errno(err)}, nil
+	}
+	fi, err := s.ops.lstat(abs)
+	if err != nil {
+		return &pb.UnlinkResponse{Error: errno(err)}, nil
+	}
+	if fi.IsDir() {
+		return &pb.UnlinkResponse{Error: errno(errIsDir)}, nil
+	}
+	if err := s.ops.remove(abs, false); err != nil {
+		return &pb.UnlinkResponse{Error: errno(err)}, nil
+	}
+	return &pb.UnlinkResponse{}, nil
+}
+
+func (s *fileSystemServer) Rename(ctx context.Context, req *pb.RenameRequest) (*pb.RenameResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.RenameResponse{Error: e}, nil
+	}
+	oldAbs, err := s.confineLink(req.OldPath)
+	if err != nil {
+		return &pb.RenameResponse{Error: errno(err)}, nil
+	}
+	newAbs, err := s.confineLink(req.NewPath)
+	if err != nil {
+		return &pb.RenameResponse{Error: errno(err)}, nil
+	}
+	if oldAbs == s.root || newAbs == s.root {
+		return &pb.RenameResponse{Error: errno(errShareRoot)}, nil
+	}
+	if req.Flags&^(renameNoReplace|renameExchange) != 0 || req.Flags == renameNoReplace|renameExchange {
+		return &pb.RenameResponse{Error: errno(errInvalidFlags)}, nil
+	}
+	if _, err := s.ops.lstat(oldAbs); err != nil {
+		return &pb.RenameResponse{Error: errno(err)}, nil
+	}
+
+	logx.Info("Rename", "old", oldAbs, "new", newAbs, "flags", req.Flags)
+
+	switch {
+	case req.Flags&renameExchange != 0:
+		if err := exchangePaths(s.ops, oldAbs, newAbs); err != nil {
+			return &pb.RenameResponse{Error: errno(err)}, nil
+		}
+		return &pb.RenameResponse{}, nil
+	case req.Flags&renameNoReplace != 0:
+		// Not atomic: a target created between the check and the rename is replaced.
+		if _, err := s.ops.lstat(newAbs); err == nil {
+			return &pb.RenameResponse{Error: errno(errExist)}, nil
+		}
+	}
+	if err := s.ops.rename(oldAbs, newAbs); err != nil {
+		return &pb.RenameResponse{Error: errno(err)}, nil
+	}
+	return &pb.RenameResponse{}, nil
+}
+
+// exchangePaths swaps two existing entries through a temporary name next to a,
+// undoing completed steps if a later one fails.
+func exchangePaths(ops fileOps, a, b string) error {
+	if _, err := ops.lstat(b); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.fsdriver-exchange-%d", a, time.Now().UnixNano())
+	if err := ops.rename(a, tmp); err != nil {
+		return err
+	}
+	if err := ops.rename(b, a); err != nil {
+		_ = ops.rename(tmp, a)
+		return err
+	}
+	if err := ops.rename(tmp, b); err != nil {
+		_ = ops.rename(a, b)
+		_ = ops.rename(tmp, a)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// openedPath returns the path the kernel has for an open file, read from
+// /proc. ok is false when /proc is not available.
+func openedPath(f *os.File) (p string, ok bool) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return "", false
+	}
+	var readErr error
+	err = rc.Control(func(fd uintptr) {
+		p, readErr = os.Readlink("/proc/self/fd/" + strconv.FormatUint(uint64(fd), 10))
+	})
+	if err != nil || readErr != nil {
+		return "", false
+	}
+	return p, true
+}
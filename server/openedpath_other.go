@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"os"
+)
+
+// openedPath is not implemented on this platform; confinement then relies
+// on resolving the path before it is opened.
+func openedPath(f *os.File) (p string, ok bool) {
+	return "", false
+}
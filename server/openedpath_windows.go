@@ -0,0 +1,36 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// openedPath returns the final path of an open file, with reparse points
+// resolved, in DOS form.
+func openedPath(f *os.File) (p string, ok bool) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return "", false
+	}
+	var pathErr error
+	err = rc.Control(func(fd uintptr) {
+		buf := make([]uint16, windows.MAX_LONG_PATH)
+		var n uint32
+		// Flags 0: FILE_NAME_NORMALIZED | VOLUME_NAME_DOS
+		n, pathErr = windows.GetFinalPathNameByHandle(windows.Handle(fd), &buf[0], uint32(len(buf)), 0)
+		if pathErr == nil && int(n) < len(buf) {
+			p = windows.UTF16ToString(buf[:n])
+		}
+	})
+	if err != nil || pathErr != nil || p == "" {
+		return "", false
+	}
+	if rest, found := strings.CutPrefix(p, `\\?\UNC\`); found {
+		return `\\` + rest, true
+	}
+	return strings.TrimPrefix(p, `\\?\`), true
+}
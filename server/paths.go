@@ -2,7 +2,10 @@ package main
 
 import (
 	"errors"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
 )
 
 func normalizeWithinRoot(root string, rel string) (string, error) {
@@ -18,7 +21,7 @@ func normalizeWithinRoot(root string, rel string) (string, error) {
 
 	if abs != root && !isSubpath(abs, root) {
 		logx.Error("path escapes root", "abs", abs, "root", root)
-		return "", errors.New("path escapes root")
+		return "", errEscapesRoot
 	}
 	return abs, nil
 }
@@ -37,3 +40,97 @@ func isSubpath(path string, root string) bool {
 func startsWithDotDot(rel string) bool {
 	return rel == ".." || (len(rel) >= 3 && (rel[:3] == "..\\" || rel[:3] == "../"))
 }
+
+// maxLinkHops bounds symlink resolution, like the kernel's ELOOP limit.
+const maxLinkHops = 40
+
+// resolveWithinRoot walks abs, a lexically confined path, component by
+// component from the share root and follows every link on the way: symbolic
+// links and, on Windows, junctions. It fails with errEscapesRoot as soon as
+// resolution leaves root, which must itself be free of links. The final
+// component is only followed when followFinal is set. Components that do not
+// exist yet end the walk, since nothing below them can be a link.
+func resolveWithinRoot(root, abs string, followFinal bool) (string, error) {
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+	pending := splitPath(rel)
+	cur := root
+	hops := 0
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			cur = filepath.Dir(cur)
+			if cur != root && !isSubpath(cur, root) {
+				return "", errEscapesRoot
+			}
+			continue
+		}
+		next := filepath.Join(cur, name)
+		if len(pending) == 0 && !followFinal {
+			return next, nil
+		}
+		target, isLink, err := linkTarget(next)
+		if errors.Is(err, fs.ErrNotExist) {
+			return filepath.Join(append([]string{next}, pending...)...), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !isLink {
+			cur = next
+			continue
+		}
+		hops++
+		if hops > maxLinkHops {
+			return "", errTooManyLinks
+		}
+		if !filepath.IsAbs(target) && filepath.VolumeName(target) == "" && len(target) > 0 && os.IsPathSeparator(target[0]) {
+			// Rooted on the current drive (Windows "\dir")
+			target = filepath.VolumeName(cur) + target
+		}
+		if filepath.IsAbs(target) {
+			target = filepath.Clean(target)
+			if target != root && !isSubpath(target, root) {
+				return "", errEscapesRoot
+			}
+			rest, _ := filepath.Rel(root, target)
+			cur = root
+			pending = append(splitPath(rest), pending...)
+			continue
+		}
+		pending = append(splitPath(target), pending...)
+	}
+	return cur, nil
+}
+
+// linkTarget returns the target of p if it is a link. Besides symbolic links
+// this covers Windows junctions, which Go reports as irregular directories.
+func linkTarget(p string) (string, bool, error) {
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return "", false, err
+	}
+	mode := fi.Mode()
+	if mode&fs.ModeSymlink == 0 && mode&fs.ModeIrregular == 0 {
+		return "", false, nil
+	}
+	target, err := os.Readlink(p)
+	if err != nil {
+		if mode&fs.ModeSymlink == 0 {
+			// Some other reparse point: not a link we can follow
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return target, true, nil
+}
+
+func splitPath(p string) []string {
+	return strings.Split(filepath.ToSlash(p), "/")
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// linkShare serves a share holding the links used by the confinement tests,
+// next to a directory outside it holding "secret".
+func linkShare(t *testing.T, opts serverOptions) (s *fileSystemServer, outside string) {
+	t.Helper()
+	s = newTestServer(t, opts)
+	outside, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mkdirs(t, s, "sub")
+	if err := os.WriteFile(filepath.Join(s.root, "sub", "file"), []byte("file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	relOut, err := filepath.Rel(s.root, outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"abs-out":   outside,
+		"rel-out":   relOut,
+		"abs-in":    filepath.Join(s.root, "sub"),
+		"rel-in":    "sub",
+		"sub/up":    "..",
+		"chain-in":  "chain-mid",
+		"chain-mid": "rel-in",
+		"chain-out": "chain-esc",
+		"chain-esc": "sub/up/rel-out",
+		"loop-a":    "loop-b",
+		"loop-b":    "loop-a",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(s.root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("symlinks not available: %v", err)
+		}
+	}
+	return s, outside
+}
+
+func TestConfineResolvesLinks(t *testing.T) {
+	s, _ := linkShare(t, serverOptions{})
+	tests := []struct {
+		rel     string
+		final   bool // Follow the final component
+		want    string
+		wantErr error
+	}{
+		{"sub/file", true, "sub/file", nil},
+		{"../outside", true, "", errEscapesRoot},
+		{"abs-out/secret", true, "", errEscapesRoot},
+		{"abs-out", true, "", errEscapesRoot},
+		{"abs-out", false, "abs-out", nil},
+		{"rel-out/secret", true, "", errEscapesRoot},
+		{"sub/up/sub/up/rel-out", true, "", errEscapesRoot},
+		{"abs-in/file", true, "sub/file", nil},
+		{"rel-in/file", true, "sub/file", nil},
+		{"chain-in/file", true, "sub/file", nil},
+		{"chain-in", false, "chain-in", nil},
+		{"chain-out/secret", true, "", errEscapesRoot},
+		{"loop-a/x", true, "", errTooManyLinks},
+		{"loop-a", true, "", errTooManyLinks},
+		{"loop-a", false, "loop-a", nil},
+		{"rel-in/new", true, "sub/new", nil},
+	}
+	for _, tt := range tests {
+		got, err := s.confinePath(tt.rel, tt.final)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("confinePath(%q, %v): error %v, want %v", tt.rel, tt.final, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && got != s.hostPath(tt.want) {
+			t.Errorf("confinePath(%q, %v) = %q, want %q", tt.rel, tt.final, got, s.hostPath(tt.want))
+		}
+	}
+}
+
+func TestConfineAllowsEscapeWhenConfigured(t *testing.T) {
+	s, outside := linkShare(t, serverOptions{allowSymlinkEscape: true})
+	for _, rel := range []string{"abs-out/secret", "rel-out/secret", "chain-out/secret"} {
+		got, err := s.confine(rel)
+		if err != nil || got != s.hostPath(rel) {
+			t.Errorf("confine(%q) = %q, %v; want the lexical path", rel, got, err)
+		}
+		data, err := os.ReadFile(got)
+		if err != nil || string(data) != "secret" {
+			t.Errorf("reading %q: %q, %v", rel, data, err)
+		}
+	}
+	// Plain ".." is still confined
+	if _, err := s.confine("../" + filepath.Base(outside)); !errors.Is(err, errEscapesRoot) {
+		t.Errorf("confine(..): error %v, want %v", err, errEscapesRoot)
+	}
+}
+
+func TestOpenThroughLinks(t *testing.T) {
+	tests := []struct {
+		rel   string
+		allow bool
+		want  int32 // Wire errno, 0 for success
+	}{
+		{"rel-in/file", false, 0},
+		{"abs-out/secret", false, codeEACCES},
+		{"rel-out/secret", false, codeEACCES},
+		{"chain-out/secret", false, codeEACCES},
+		{"loop-a", false, codeELOOP},
+		{"abs-out/secret", true, 0},
+		{"chain-out/secret", true, 0},
+	}
+	for _, tt := range tests {
+		s, _ := linkShare(t, serverOptions{allowSymlinkEscape: tt.allow})
+		resp, err := s.Open(context.Background(), &pb.OpenRequest{Path: tt.rel})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got int32
+		if e := resp.GetError(); e != nil {
+			got = e.Code
+		}
+		if got != tt.want {
+			t.Errorf("Open(%q, allow=%v): errno %d, want %d", tt.rel, tt.allow, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOpenedRejectsSwappedPath(t *testing.T) {
+	s, outside := linkShare(t, serverOptions{})
+	if _, ok := openedPath(mustOpen(t, s.hostPath("sub/file"))); !ok {
+		t.Skip("open file paths not available on this platform")
+	}
+
+	// What a link swapped in between confine and open would yield
+	f := mustOpen(t, filepath.Join(outside, "secret"))
+	if err := s.checkOpened(f); !errors.Is(err, errEscapesRoot) {
+		t.Fatalf("checkOpened outside the share: %v, want %v", err, errEscapesRoot)
+	}
+	if err := f.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Error("rejected file left open")
+	}
+	if err := s.checkOpened(mustOpen(t, s.hostPath("sub/file"))); err != nil {
+		t.Fatalf("checkOpened inside the share: %v", err)
+	}
+}
+
+func mustOpen(t *testing.T, p string) *os.File {
+	t.Helper()
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// defaultStreamChunk is the ReadStream chunk size when the client has no
+// preference; chunks never exceed the server's maximum read size.
+const defaultStreamChunk = 256 << 10
+
+// GetCapabilities reports the server's limits. A client proposing a smaller
+// read size gets that instead, so both sides agree on the maximum.
+func (s *fileSystemServer) GetCapabilities(ctx context.Context, req *pb.CapabilitiesRequest) (*pb.CapabilitiesResponse, error) {
+	limit := s.opts.maxReadSize
+	if req.MaxReadSize > 0 {
+		limit = min(limit, req.MaxReadSize)
+	}
+	return &pb.CapabilitiesResponse{MaxReadSize: limit}, nil
+}
+
+// Read returns up to req.Size bytes at req.Offset; requests above the maximum
+// read size are served short, like a POSIX read.
+func (s *fileSystemServer) Read(ctx context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
+	h := s.getHandle(ctx, req.Handle)
+	if h == nil {
+		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: errno(errUnknownHandle)}}, nil
+	}
+	if req.Offset < 0 || req.Size < 0 {
+		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: errno(errInvalidOffset)}}, nil
+	}
+	data, err := s.readAt(h.file, req.Offset, min(req.Size, s.opts.maxReadSize))
+	if err != nil {
+		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: errno(err)}}, nil
+	}
+	return &pb.ReadResponse{Result: &pb.ReadResponse_Data{Data: data}}, nil
+}
+
+// ReadStream sends a range of an open file as a sequence of chunks. Send
+// blocks once the HTTP/2 flow-control window is full, so a slow client
+// throttles the server instead of making it buffer the file.
+func (s *fileSystemServer) ReadStream(req *pb.ReadStreamRequest, stream pb.FileSystemService_ReadStreamServer) error {
+	sendErr := func(err error) error {
+		return stream.Send(&pb.ReadChunk{Offset: req.Offset, Result: &pb.ReadChunk_Error{Error: errno(err)}})
+	}
+	h := s.getHandle(stream.Context(), req.Handle)
+	if h == nil {
+		return sendErr(errUnknownHandle)
+	}
+	if req.Offset < 0 || req.Length < 0 || req.ChunkSize < 0 {
+		return sendErr(errInvalidOffset)
+	}
+	chunk := req.ChunkSize
+	if chunk == 0 {
+		chunk = defaultStreamChunk
+	}
+	chunk = min(chunk, s.opts.maxReadSize)
+
+	off := req.Offset
+	remaining := req.Length
+	for req.Length == 0 || remaining > 0 {
+		size := chunk
+		if req.Length > 0 {
+			size = int32(min(int64(size), remaining))
+		}
+		data, err := s.readAt(h.file, off, size)
+		h.touch()
+		if err != nil {
+			return stream.Send(&pb.ReadChunk{Offset: off, Result: &pb.ReadChunk_Error{Error: errno(err)}})
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		if err := stream.Send(&pb.ReadChunk{Offset: off, Result: &pb.ReadChunk_Data{Data: data}}); err != nil {
+			return err
+		}
+		off += int64(len(data))
+		remaining -= int64(len(data))
+		if len(data) < int(size) {
+			return nil // EOF
+		}
+	}
+	return nil
+}
+
+// readAt reads up to size bytes (at most the maximum read size) at off. The
+// read goes through a pooled buffer and only the bytes actually read are
+// copied out, so a large size near EOF does not cost a large allocation; the
+// copy is needed because gRPC marshals the response after the handler
+// returns. A short result means EOF.
+func (s *fileSystemServer) readAt(f *os.File, off int64, size int32) ([]byte, error) {
+	bp := s.readBufs.Get().(*[]byte)
+	defer s.readBufs.Put(bp)
+
+	buf := (*bp)[:size]
+	n, err := f.ReadAt(buf, off)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return append([]byte(nil), buf[:n]...), nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// testPattern returns n bytes in which every aligned 4 KiB block differs.
+func testPattern(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i) ^ byte(i>>12)
+	}
+	return data
+}
+
+// openTestFile writes data to name in the share and opens it.
+func openTestFile(t *testing.T, s *fileSystemServer, name string, data []byte) int32 {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(s.root, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.Open(context.Background(), &pb.OpenRequest{Path: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := resp.GetError(); e != nil {
+		t.Fatalf("Open(%q): %v", name, e)
+	}
+	return resp.GetHandle()
+}
+
+func TestConcurrentReadsOfOneHandle(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	const (
+		chunk   = 16 << 10
+		readers = 64
+		rounds  = 8
+	)
+	data := testPattern(readers * chunk)
+	hid := openTestFile(t, s, "f", data)
+
+	var wg sync.WaitGroup
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				// Each round every reader moves on to another reader's range
+				off := int64((r+i)%readers) * chunk
+				resp, err := s.Read(context.Background(), &pb.ReadRequest{Handle: hid, Offset: off, Size: chunk})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if e := resp.GetError(); e != nil {
+					t.Errorf("Read at %d: %v", off, e)
+					return
+				}
+				if !bytes.Equal(resp.GetData(), data[off:off+chunk]) {
+					t.Errorf("Read at %d returned the wrong bytes", off)
+					return
+				}
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+func TestReadBounds(t *testing.T) {
+	s := newTestServer(t, serverOptions{maxReadSize: 4096})
+	data := testPattern(10000)
+	hid := openTestFile(t, s, "f", data)
+
+	tests := []struct {
+		name  string
+		req   *pb.ReadRequest
+		want  []byte
+		errno int32
+	}{
+		{"within limit", &pb.ReadRequest{Handle: hid, Offset: 100, Size: 1000}, data[100:1100], 0},
+		{"short above max read size", &pb.ReadRequest{Handle: hid, Offset: 0, Size: 8192}, data[:4096], 0},
+		{"short at EOF", &pb.ReadRequest{Handle: hid, Offset: 9000, Size: 4096}, data[9000:], 0},
+		{"at EOF", &pb.ReadRequest{Handle: hid, Offset: 10000, Size: 4096}, nil, 0},
+		{"past EOF", &pb.ReadRequest{Handle: hid, Offset: 1 << 40, Size: 4096}, nil, 0},
+		{"zero size", &pb.ReadRequest{Handle: hid, Offset: 0, Size: 0}, nil, 0},
+		{"negative offset", &pb.ReadRequest{Handle: hid, Offset: -1, Size: 10}, nil, codeEINVAL},
+		{"negative size", &pb.ReadRequest{Handle: hid, Offset: 0, Size: -1}, nil, codeEINVAL},
+		{"unknown handle", &pb.ReadRequest{Handle: hid + 1, Offset: 0, Size: 10}, nil, codeEBADF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Read(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e := resp.GetError(); e != nil || tt.errno != 0 {
+				if e.GetCode() != tt.errno {
+					t.Fatalf("error %v, want errno %d", e, tt.errno)
+				}
+				return
+			}
+			if !bytes.Equal(resp.GetData(), tt.want) {
+				t.Errorf("got %d bytes, want %d", len(resp.GetData()), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestConcurrentReadsSharingBuffers(t *testing.T) {
+	s := newTestServer(t, serverOptions{maxReadSize: 8192})
+	files := make([][]byte, 4)
+	handles := make([]int32, len(files))
+	for i := range files {
+		// Sizes that are not multiples of the buffer, so reads end short
+		files[i] = testPattern(50000 + 1000*i)
+		for j := range files[i] {
+			files[i][j] += byte(i)
+		}
+		handles[i] = openTestFile(t, s, string(rune('a'+i)), files[i])
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		kept [][2][]byte // Results held while the buffers are reused
+	)
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				f := (g + i) % len(files)
+				off := int64((g*7919 + i*104729) % len(files[f]))
+				size := int32(1 + (g*31+i*17)%12000) // Sometimes above the limit
+				resp, err := s.Read(context.Background(), &pb.ReadRequest{Handle: handles[f], Offset: off, Size: size})
+				if err != nil || resp.GetError() != nil {
+					t.Errorf("Read: %v %v", err, resp.GetError())
+					return
+				}
+				end := min(off+int64(min(size, 8192)), int64(len(files[f])))
+				want := files[f][off:end]
+				got := resp.GetData()
+				if !bytes.Equal(got, want) {
+					t.Errorf("file %d at %d: wrong bytes", f, off)
+					return
+				}
+				mu.Lock()
+				kept = append(kept, [2][]byte{got, want})
+				mu.Unlock()
+			}
+		}(g)
+	}
+	wg.Wait()
+	for _, k := range kept {
+		if !bytes.Equal(k[0], k[1]) {
+			t.Fatal("a returned slice was overwritten by a later read")
+		}
+	}
+}
+
+func TestGetCapabilities(t *testing.T) {
+	s := newTestServer(t, serverOptions{maxReadSize: 1 << 20})
+	tests := []struct {
+		proposed, want int32
+	}{
+		{0, 1 << 20},
+		{64 << 10, 64 << 10},
+		{1 << 20, 1 << 20},
+		{2 << 20, 1 << 20},
+		{-1, 1 << 20},
+	}
+	for _, tt := range tests {
+		resp, err := s.GetCapabilities(context.Background(), &pb.CapabilitiesRequest{MaxReadSize: tt.proposed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.MaxReadSize != tt.want {
+			t.Errorf("client proposing %d: max read size %d, want %d", tt.proposed, resp.MaxReadSize, tt.want)
+		}
+	}
+}
@@ -6,137 +6,382 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	pb "github.com/example/fsdriver/proto"
 )
 
+// serverOptions carries the tunables passed from the command line.
+type serverOptions struct {
+	readOnly             bool
+	handleIdleTimeout    time.Duration // 0 disables idle handle reaping
+	maxHandlesPerSession int           // 0 means unlimited
+	maxReadSize          int32         // Largest Read size and ReadStream chunk served
+	allowSymlinkEscape   bool          // Follow links that lead out of the share
+	watchDebounce        time.Duration // Window for coalescing watch events per path; 0 sends immediately
+}
+
 type fileSystemServer struct {
 	pb.UnimplementedFileSystemServiceServer
-	root         string
-	mu           sync.Mutex
-	nextHandleID int32
-	handles      map[int32]*fileHandle
+	root     string // With links resolved, so confined paths stay below it
+	opts     serverOptions
+	mu       sync.Mutex
+	handles  map[int32]*fileHandle
+	sessions map[uint64]map[int32]struct{} // Handle IDs owned by each session
+	cursors  map[string]*dirCursor         // Paginated directory listings by token
+	readBufs sync.Pool                     // *[]byte of maxReadSize bytes
+	ids      *fileIndex
+	watches  *watchHub
+	ops      fileOps
 }
 
-func NewFileSystemServer(root string) (*fileSystemServer, error) {
+func NewFileSystemServer(root string, opts serverOptions) (*fileSystemServer, error) {
 	abs, err := filepath.Abs(root)
 	if err != nil {
 		return nil, err
 	}
-	return &fileSystemServer{root: abs, handles: make(map[int32]*fileHandle)}, nil
+	realRoot, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, err
+	}
+	s := &fileSystemServer{
+		root:     realRoot,
+		opts:     opts,
+		handles:  make(map[int32]*fileHandle),
+		sessions: make(map[uint64]map[int32]struct{}),
+		cursors:  make(map[string]*dirCursor),
+		ids:      newFileIndex(),
+	}
+	s.ops = newFileOps(s)
+	s.watches = newWatchHub(s)
+	s.readBufs.New = func() any {
+		buf := make([]byte, opts.maxReadSize)
+		return &buf
+	}
+	return s, nil
 }
 
+// readOnlyError returns EROFS for mutating requests when the share is exported read-only.
+func (s *fileSystemServer) readOnlyError() *pb.Error {
+	if !s.opts.readOnly {
+		return nil
+	}
+	return errno(errReadOnly)
+}
+
+// confine maps a client path to a host path inside the share. Unless
+// escaping links are allowed, links on the way, including one in the final
+// component, must resolve inside the share too, and the path returned is the
+// resolved one. This only checks the path: on Linux s.ops refuses a link
+// swapped in afterwards, while elsewhere such a link can still redirect an
+// operation and only opened files are checked, after the fact.
 func (s *fileSystemServer) confine(rel string) (string, error) {
-	return normalizeWithinRoot(s.root, rel)
+	return s.confinePath(rel, true)
+}
+
+// confineLink is confine for operations that act on a link itself rather
+// than on its target, such as Lstat, Unlink, Rename and Readlink.
+func (s *fileSystemServer) confineLink(rel string) (string, error) {
+	return s.confinePath(rel, false)
+}
+
+func (s *fileSystemServer) confinePath(rel string, followFinal bool) (string, error) {
+	abs, err := normalizeWithinRoot(s.root, rel)
+	if err != nil || s.opts.allowSymlinkEscape {
+		return abs, err
+	}
+	resolved, err := resolveWithinRoot(s.root, abs, followFinal)
+	if err != nil {
+		if err == errEscapesRoot {
+			logx.Error("path escapes root through a link", "abs", abs, "root", s.root)
+		}
+		return "", err
+	}
+	return resolved, nil
+}
+
+// checkOpened verifies that f, opened from a confined path, really is inside
+// the share. It closes f if not. Whatever opening did, such as creating or
+// truncating the file, is not undone.
+func (s *fileSystemServer) checkOpened(f *os.File) error {
+	if s.opts.allowSymlinkEscape {
+		return nil
+	}
+	p, ok := openedPath(f)
+	if !ok || p == s.root || isSubpath(p, s.root) {
+		return nil
+	}
+	logx.Error("opened file escapes root", "path", p, "root", s.root)
+	_ = f.Close()
+	return errEscapesRoot
 }
 
-func (s *fileSystemServer) toFileInfo(fi os.FileInfo) *pb.FileInfo {
+// fileMetadata is what FileInfo reports beyond os.FileInfo; fileMeta
+// gathers it the cheapest way the platform allows.
+type fileMetadata struct {
+	dev, ino            uint64
+	nlink               uint32
+	atime, ctime, btime time.Time
+}
+
+func (s *fileSystemServer) toFileInfo(absPath string, fi os.FileInfo) *pb.FileInfo {
 	mode := uint32(fi.Mode().Perm())
-	ts := fi.ModTime().Unix()
-	return &pb.FileInfo{
-		Name:       fi.Name(),
-		IsDir:      fi.IsDir(),
-		Size:       fi.Size(),
-		ModTime:    ts,
-		AccessTime: ts,
-		ChangeTime: ts,
-		Mode:       mode,
-		Uid:        0,
-		Gid:        0,
-		IsSymlink:  fi.Mode()&os.ModeSymlink != 0,
+	mtime := fi.ModTime()
+	meta := fileMeta(absPath, fi)
+	atime, ctime, btime := meta.atime, meta.ctime, meta.btime
+	s.ids.record(absPath, fileKey{meta.dev, meta.ino})
+	info := &pb.FileInfo{
+		Name:           fi.Name(),
+		IsDir:          fi.IsDir(),
+		Size:           fi.Size(),
+		ModTime:        mtime.Unix(),
+		ModTimeNsec:    uint32(mtime.Nanosecond()),
+		AccessTime:     atime.Unix(),
+		AccessTimeNsec: uint32(atime.Nanosecond()),
+		ChangeTime:     ctime.Unix(),
+		ChangeTimeNsec: uint32(ctime.Nanosecond()),
+		Mode:           mode,
+		Uid:            0,
+		Gid:            0,
+		IsSymlink:      fi.Mode()&os.ModeSymlink != 0,
+		Dev:            meta.dev,
+		Ino:            meta.ino,
+		Nlink:          meta.nlink,
+	}
+	if !btime.IsZero() {
+		info.BirthTime = btime.Unix()
+		info.BirthTimeNsec = uint32(btime.Nanosecond())
 	}
+	return info
 }
 
 func (s *fileSystemServer) Stat(ctx context.Context, req *pb.StatRequest) (*pb.StatResponse, error) {
-	abs, err := s.confine(req.Path)
+	abs, err := s.confineLink(req.Path)
 	if err != nil {
 		return &pb.StatResponse{Result: &pb.StatResponse_Error{Error: errno(err)}}, nil
 	}
-	fi, err := os.Lstat(abs)
+	fi, err := s.ops.lstat(abs)
 	if err != nil {
 		return &pb.StatResponse{Result: &pb.StatResponse_Error{Error: errno(err)}}, nil
 	}
-	return &pb.StatResponse{Result: &pb.StatResponse_Info{Info: s.toFileInfo(fi)}}, nil
+	return &pb.StatResponse{Result: &pb.StatResponse_Info{Info: s.toFileInfo(abs, fi)}}, nil
 }
 
 func (s *fileSystemServer) ReadDir(ctx context.Context, req *pb.ReadDirRequest) (*pb.ReadDirResponse, error) {
-	logx.Info("ReadDir request", "path", req.Path, "offset", req.Offset, "limit", req.Limit)
+	logx.Info("ReadDir request", "path", req.Path, "limit", req.Limit, "continued", req.Cursor != "", "release", req.Release)
+	// Older clients paged by offset; serving them the first page again
+	// would never end their listing
+	if req.Cursor == "" && req.Offset != 0 {
+		return &pb.ReadDirResponse{Error: errno(errLegacyOffset)}, nil
+	}
+	if req.Release {
+		c := s.takeCursor(ctx, req.Cursor)
+		if c == nil {
+			return &pb.ReadDirResponse{Error: errno(errInvalidCursor)}, nil
+		}
+		_ = c.dir.Close()
+		return &pb.ReadDirResponse{}, nil
+	}
 	abs, err := s.confine(req.Path)
 	if err != nil {
 		logx.Error("ReadDir path confinement failed", "path", req.Path, "error", err)
 		return &pb.ReadDirResponse{Error: errno(err)}, nil
 	}
-	logx.Info("ReadDir confined path", "original", req.Path, "absolute", abs)
-	f, err := os.Open(abs)
-	if err != nil {
-		logx.Error("ReadDir open failed", "path", abs, "error", err)
-		return &pb.ReadDirResponse{Error: errno(err)}, nil
+
+	var c *dirCursor
+	if req.Cursor != "" {
+		c = s.takeCursor(ctx, req.Cursor)
+		if c == nil {
+			return &pb.ReadDirResponse{Error: errno(errInvalidCursor)}, nil
+		}
+		if c.absPath != abs {
+			_ = c.dir.Close()
+			return &pb.ReadDirResponse{Error: errno(errInvalidCursor)}, nil
+		}
+	} else {
+		f, err := s.ops.openFile(abs, os.O_RDONLY, 0)
+		if err != nil {
+			logx.Error("ReadDir open failed", "path", abs, "error", err)
+			return &pb.ReadDirResponse{Error: errno(err)}, nil
+		}
+		c = &dirCursor{absPath: abs, dir: f}
 	}
-	defer f.Close()
 
-	// Pagination
-	offset := int(req.Offset)
 	limit := int(req.Limit)
-	if limit < 0 {
-		limit = 0
+	if limit <= 0 {
+		limit = defaultDirPage
 	}
-	entries, err := f.Readdir(0)
+	limit = min(limit, maxDirPage)
+	entries, err := c.dir.Readdir(limit)
 	if err != nil && err != io.EOF {
+		_ = c.dir.Close()
 		return &pb.ReadDirResponse{Error: errno(err)}, nil
 	}
-	var out []*pb.FileInfo
-	for i := offset; i < len(entries); i++ {
-		if limit > 0 && len(out) >= limit {
-			break
-		}
-		out = append(out, s.toFileInfo(entries[i]))
+	out := make([]*pb.FileInfo, 0, len(entries))
+	for _, fi := range entries {
+		out = append(out, s.toFileInfo(filepath.Join(abs, fi.Name()), fi))
+	}
+
+	// A full page may be followed by more entries; the next call finds out.
+	resp := &pb.ReadDirResponse{Entries: out, HasMore: len(entries) == limit}
+	if resp.HasMore {
+		resp.NextCursor = s.storeCursor(ctx, c)
+	} else {
+		_ = c.dir.Close()
 	}
-	hasMore := (offset+len(out) < len(entries))
-	logx.Info("ReadDir response", "entries_returned", len(out), "total_entries", len(entries), "has_more", hasMore)
-	return &pb.ReadDirResponse{Entries: out, HasMore: hasMore}, nil
+	logx.Info("ReadDir response", "entries_returned", len(out), "has_more", resp.HasMore)
+	return resp, nil
 }
 
 func (s *fileSystemServer) Open(ctx context.Context, req *pb.OpenRequest) (*pb.OpenResponse, error) {
+	if isWriteOpen(req.Flags) {
+		if e := s.readOnlyError(); e != nil {
+			return &pb.OpenResponse{Result: &pb.OpenResponse_Error{Error: e}}, nil
+		}
+	}
 	abs, err := s.confine(req.Path)
 	if err != nil {
 		return &pb.OpenResponse{Result: &pb.OpenResponse_Error{Error: errno(err)}}, nil
 	}
-	f, err := os.Open(abs)
+	flags := openFlags(req.Flags)
+	f, err := s.ops.openFile(abs, flags, 0o644)
+	if err != nil {
+		return &pb.OpenResponse{Result: &pb.OpenResponse_Error{Error: errno(err)}}, nil
+	}
+	hid, err := s.registerHandle(ctx, abs, f, flags)
 	if err != nil {
+		_ = f.Close()
 		return &pb.OpenResponse{Result: &pb.OpenResponse_Error{Error: errno(err)}}, nil
 	}
-	hid := s.registerHandle(abs, f)
 	return &pb.OpenResponse{Result: &pb.OpenResponse_Handle{Handle: hid}}, nil
 }
 
-func (s *fileSystemServer) Read(ctx context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
-	h := s.getHandle(req.Handle)
+func (s *fileSystemServer) Close(ctx context.Context, req *pb.CloseRequest) (*pb.CloseResponse, error) {
+	h := s.takeHandle(ctx, req.Handle)
 	if h == nil {
-		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: &pb.Error{Code: int32(2), Message: "bad handle"}}}, nil
+		return &pb.CloseResponse{Error: errno(errUnknownHandle)}, nil
 	}
-	if req.Offset < 0 || req.Size < 0 {
-		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: &pb.Error{Code: int32(22), Message: "invalid offset/size"}}}, nil
+	_ = h.file.Close()
+	return &pb.CloseResponse{}, nil
+}
+
+func (s *fileSystemServer) Create(ctx context.Context, req *pb.CreateRequest) (*pb.CreateResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.CreateResponse{Error: e}, nil
 	}
-	if _, err := h.file.Seek(req.Offset, io.SeekStart); err != nil {
-		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: errno(err)}}, nil
+	abs, err := s.confine(req.Path)
+	if err != nil {
+		return &pb.CreateResponse{Error: errno(err)}, nil
+	}
+	mode := os.FileMode(req.Mode).Perm()
+	if mode == 0 {
+		mode = 0o644
 	}
-	buf := make([]byte, req.Size)
-	n, err := io.ReadFull(h.file, buf)
-	if err == io.ErrUnexpectedEOF || err == io.EOF {
-		// partial read at EOF is fine
-		return &pb.ReadResponse{Result: &pb.ReadResponse_Data{Data: buf[:n]}}, nil
+	flags := openFlags(req.Flags) | os.O_CREATE
+	f, err := s.ops.openFile(abs, flags, mode)
+	if err != nil {
+		return &pb.CreateResponse{Error: errno(err)}, nil
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return &pb.CreateResponse{Error: errno(err)}, nil
 	}
+	hid, err := s.registerHandle(ctx, abs, f, flags)
 	if err != nil {
-		return &pb.ReadResponse{Result: &pb.ReadResponse_Error{Error: errno(err)}}, nil
+		_ = f.Close()
+		return &pb.CreateResponse{Error: errno(err)}, nil
 	}
-	return &pb.ReadResponse{Result: &pb.ReadResponse_Data{Data: buf[:n]}}, nil
+	return &pb.CreateResponse{Handle: hid, Info: s.toFileInfo(abs, fi)}, nil
 }
 
-func (s *fileSystemServer) Close(ctx context.Context, req *pb.CloseRequest) (*pb.CloseResponse, error) {
-	h := s.takeHandle(req.Handle)
+func (s *fileSystemServer) Write(ctx context.Context, req *pb.WriteRequest) (*pb.WriteResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.WriteResponse{Result: &pb.WriteResponse_Error{Error: e}}, nil
+	}
+	h := s.getHandle(ctx, req.Handle)
 	if h == nil {
-		return &pb.CloseResponse{Error: &pb.Error{Code: int32(2), Message: "bad handle"}}, nil
+		return &pb.WriteResponse{Result: &pb.WriteResponse_Error{Error: errno(errUnknownHandle)}}, nil
 	}
-	_ = h.file.Close()
-	return &pb.CloseResponse{}, nil
+	if req.Offset < 0 {
+		return &pb.WriteResponse{Result: &pb.WriteResponse_Error{Error: errno(errInvalidOffset)}}, nil
+	}
+	var n int
+	var err error
+	if h.flags&os.O_APPEND != 0 {
+		// WriteAt is rejected on O_APPEND files; the OS appends regardless of offset.
+		n, err = h.file.Write(req.Data)
+	} else {
+		n, err = h.file.WriteAt(req.Data, req.Offset)
+	}
+	if err != nil {
+		return &pb.WriteResponse{Result: &pb.WriteResponse_Error{Error: errno(err)}}, nil
+	}
+	return &pb.WriteResponse{Result: &pb.WriteResponse_Written{Written: int32(n)}}, nil
+}
+
+func (s *fileSystemServer) Truncate(ctx context.Context, req *pb.TruncateRequest) (*pb.TruncateResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.TruncateResponse{Error: e}, nil
+	}
+	if req.Size < 0 {
+		return &pb.TruncateResponse{Error: errno(errInvalidSize)}, nil
+	}
+	if req.Handle != 0 {
+		h := s.getHandle(ctx, req.Handle)
+		if h == nil {
+			return &pb.TruncateResponse{Error: errno(errUnknownHandle)}, nil
+		}
+		if err := h.file.Truncate(req.Size); err != nil {
+			return &pb.TruncateResponse{Error: errno(err)}, nil
+		}
+		return &pb.TruncateResponse{}, nil
+	}
+	abs, err := s.confine(req.Path)
+	if err != nil {
+		return &pb.TruncateResponse{Error: errno(err)}, nil
+	}
+	f, err := s.ops.openFile(abs, os.O_WRONLY, 0)
+	if err != nil {
+		return &pb.TruncateResponse{Error: errno(err)}, nil
+	}
+	defer f.Close()
+	if err := f.Truncate(req.Size); err != nil {
+		return &pb.TruncateResponse{Error: errno(err)}, nil
+	}
+	return &pb.TruncateResponse{}, nil
+}
+
+// Chtimes sets the access and modification time of a file, following a
+// final symlink like utimensat without AT_SYMLINK_NOFOLLOW.
+func (s *fileSystemServer) Chtimes(ctx context.Context, req *pb.ChtimesRequest) (*pb.ChtimesResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.ChtimesResponse{Error: e}, nil
+	}
+	if req.AtimeNsec >= 1e9 || req.MtimeNsec >= 1e9 {
+		return &pb.ChtimesResponse{Error: errno(errInvalidTime)}, nil
+	}
+	abs, err := s.confine(req.Path)
+	if err != nil {
+		return &pb.ChtimesResponse{Error: errno(err)}, nil
+	}
+	// A zero time leaves that time unchanged
+	now := time.Now()
+	var atime, mtime time.Time
+	switch {
+	case req.SetAtime && req.AtimeNow:
+		atime = now
+	case req.SetAtime:
+		atime = time.Unix(req.Atime, int64(req.AtimeNsec))
+	}
+	switch {
+	case req.SetMtime && req.MtimeNow:
+		mtime = now
+	case req.SetMtime:
+		mtime = time.Unix(req.Mtime, int64(req.MtimeNsec))
+	}
+	if err := s.ops.chtimes(abs, atime, mtime); err != nil {
+		return &pb.ChtimesResponse{Error: errno(err)}, nil
+	}
+	return &pb.ChtimesResponse{}, nil
 }
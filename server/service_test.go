@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// newTestServer serves a fresh temporary directory.
+func newTestServer(t *testing.T, opts serverOptions) *fileSystemServer {
+	t.Helper()
+	if opts.maxReadSize == 0 {
+		opts.maxReadSize = 1 << 20
+	}
+	s, err := NewFileSystemServer(t.TempDir(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// mkdirs creates directories below the share root.
+func mkdirs(t *testing.T, s *fileSystemServer, rels ...string) {
+	t.Helper()
+	for _, rel := range rels {
+		if err := os.MkdirAll(filepath.Join(s.root, filepath.FromSlash(rel)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestChtimes(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	p := filepath.Join(s.root, "f")
+	if err := os.WriteFile(p, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	mtimeOf := func() time.Time {
+		t.Helper()
+		fi, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.ModTime()
+	}
+
+	want := time.Unix(1600000000, 123456700)
+	resp, err := s.Chtimes(ctx, &pb.ChtimesRequest{Path: "f", SetMtime: true, Mtime: want.Unix(), MtimeNsec: uint32(want.Nanosecond())})
+	if err != nil || resp.Error != nil {
+		t.Fatalf("Chtimes: %v, %v", resp.GetError(), err)
+	}
+	if got := mtimeOf(); !got.Equal(want) {
+		t.Errorf("mtime %v, want %v", got, want)
+	}
+
+	// Only flagged times change
+	resp, _ = s.Chtimes(ctx, &pb.ChtimesRequest{Path: "f", SetAtime: true, Atime: 1500000000})
+	if resp.Error != nil || !mtimeOf().Equal(want) {
+		t.Errorf("setting atime: %v, mtime now %v", resp.Error, mtimeOf())
+	}
+
+	before := time.Now().Add(-time.Second)
+	resp, _ = s.Chtimes(ctx, &pb.ChtimesRequest{Path: "f", SetMtime: true, MtimeNow: true, Mtime: 1})
+	if resp.Error != nil || mtimeOf().Before(before) {
+		t.Errorf("setting mtime to now: %v, mtime %v", resp.Error, mtimeOf())
+	}
+
+	tests := []struct {
+		name string
+		opts serverOptions
+		req  *pb.ChtimesRequest
+		want int32
+	}{
+		{"missing", serverOptions{}, &pb.ChtimesRequest{Path: "nope", SetMtime: true}, codeENOENT},
+		{"outside", serverOptions{}, &pb.ChtimesRequest{Path: "../f", SetMtime: true}, codeEACCES},
+		{"nanoseconds", serverOptions{}, &pb.ChtimesRequest{Path: "f", SetMtime: true, MtimeNsec: 1e9}, codeEINVAL},
+		{"read-only", serverOptions{readOnly: true}, &pb.ChtimesRequest{Path: "f", SetMtime: true}, codeEROFS},
+	}
+	for _, tt := range tests {
+		s := newTestServer(t, tt.opts)
+		if err := os.WriteFile(filepath.Join(s.root, "f"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := s.Chtimes(ctx, tt.req)
+		if err != nil || resp.GetError().GetCode() != tt.want {
+			t.Errorf("%s: %v, %v; want errno %d", tt.name, resp.GetError(), err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/stats"
+)
+
+// A session is one client connection. Handles are owned by the session that
+// opened them and are closed when its connection ends.
+
+type sessionKey struct{}
+
+// sessionFromContext returns the session of the connection an RPC arrived
+// on; 0 if the server was not set up with a sessionTracker.
+func sessionFromContext(ctx context.Context) uint64 {
+	id, _ := ctx.Value(sessionKey{}).(uint64)
+	return id
+}
+
+// sessionTracker is a gRPC stats handler that tags every connection with a
+// session ID and releases the session's handles when the connection closes.
+type sessionTracker struct {
+	srv  *fileSystemServer
+	next atomic.Uint64
+}
+
+func newSessionTracker(srv *fileSystemServer) *sessionTracker {
+	return &sessionTracker{srv: srv}
+}
+
+func (t *sessionTracker) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	id := t.next.Add(1)
+	logx.Info("client session started", "session", id, "client_addr", info.RemoteAddr)
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+func (t *sessionTracker) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnEnd); !ok {
+		return
+	}
+	id := sessionFromContext(ctx)
+	closed := t.srv.closeSession(id)
+	logx.Info("client session ended", "session", id, "handles_closed", closed)
+}
+
+func (t *sessionTracker) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (t *sessionTracker) HandleRPC(context.Context, stats.RPCStats) {}
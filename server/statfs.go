@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// Statfs reports the capacity of the volume holding the given path. Sizes are
+// those of the whole volume, not of the share directory.
+func (s *fileSystemServer) Statfs(ctx context.Context, req *pb.StatfsRequest) (*pb.StatfsResponse, error) {
+	abs, err := s.confine(req.Path)
+	if err != nil {
+		return &pb.StatfsResponse{Result: &pb.StatfsResponse_Error{Error: errno(err)}}, nil
+	}
+	stats, err := volumeStats(abs)
+	if err != nil {
+		logx.Error("Statfs failed", "path", abs, "error", err)
+		return &pb.StatfsResponse{Result: &pb.StatfsResponse_Error{Error: errno(err)}}, nil
+	}
+	return &pb.StatfsResponse{Result: &pb.StatfsResponse_Stats{Stats: stats}}, nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package main
+
+import (
+	"syscall"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// volumeStats returns statfs(2) figures for the volume holding absPath.
+func volumeStats(absPath string) (*pb.FsStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(absPath, &st); err != nil {
+		return nil, err
+	}
+	// Block counts are in fragment-size units
+	unit := uint64(st.Frsize)
+	if unit == 0 {
+		unit = uint64(st.Bsize)
+	}
+	return &pb.FsStats{
+		TotalBytes:     st.Blocks * unit,
+		FreeBytes:      st.Bfree * unit,
+		AvailableBytes: st.Bavail * unit,
+		TotalFiles:     st.Files,
+		FreeFiles:      st.Ffree,
+		BlockSize:      uint32(unit),
+		MaxNameLength:  uint32(st.Namelen),
+	}, nil
+}
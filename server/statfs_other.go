@@ -0,0 +1,12 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	pb "github.com/example/fsdriver/proto"
+)
+
+// volumeStats is not implemented on this platform.
+func volumeStats(absPath string) (*pb.FsStats, error) {
+	return nil, &posixError{codeENOSYS, "statfs not supported on this platform"}
+}
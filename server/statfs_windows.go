@@ -0,0 +1,65 @@
+//go:build windows
+
+package main
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// x/sys/windows wraps only the Ex variant, which does not report the cluster size
+var procGetDiskFreeSpaceW = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetDiskFreeSpaceW")
+
+// volumeStats queries the volume holding absPath. NTFS has no fixed inode
+// table, so file counts are reported as 0.
+func volumeStats(absPath string) (*pb.FsStats, error) {
+	p, err := windows.UTF16PtrFromString(absPath)
+	if err != nil {
+		return nil, err
+	}
+	// Available honours per-user quotas; free is the whole volume
+	var avail, total, free uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &avail, &total, &free); err != nil {
+		return nil, err
+	}
+
+	stats := &pb.FsStats{
+		TotalBytes:     total,
+		FreeBytes:      free,
+		AvailableBytes: avail,
+		BlockSize:      4096,
+		MaxNameLength:  255,
+	}
+	root, err := volumeRoot(absPath)
+	if err != nil {
+		return stats, nil
+	}
+	var sectorsPerCluster, bytesPerSector, freeClusters, totalClusters uint32
+	if ok, _, _ := procGetDiskFreeSpaceW.Call(uintptr(unsafe.Pointer(root)),
+		uintptr(unsafe.Pointer(&sectorsPerCluster)), uintptr(unsafe.Pointer(&bytesPerSector)),
+		uintptr(unsafe.Pointer(&freeClusters)), uintptr(unsafe.Pointer(&totalClusters))); ok != 0 {
+		stats.BlockSize = sectorsPerCluster * bytesPerSector
+	}
+	var maxComponent, flags uint32
+	if windows.GetVolumeInformation(root, nil, 0, nil, &maxComponent, &flags, nil, 0) == nil {
+		stats.MaxNameLength = maxComponent
+	}
+	return stats, nil
+}
+
+// volumeRoot returns the mount point of the volume holding absPath, with the
+// trailing backslash the volume APIs require.
+func volumeRoot(absPath string) (*uint16, error) {
+	p, err := windows.UTF16PtrFromString(absPath)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]uint16, windows.MAX_PATH+1)
+	if err := windows.GetVolumePathName(p, &buf[0], uint32(len(buf))); err != nil {
+		return nil, err
+	}
+	return &buf[0], nil
+}
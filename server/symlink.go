@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// Symlink targets are exchanged in POSIX form: '/'-separated and relative to
+// the link's directory, so the client's kernel resolves them inside the mount.
+//
+// On Windows only true symbolic links are reported as symlinks. Junctions and
+// other reparse points are presented as the directory or file they behave as
+// (Go reports junctions as directories), so they are traversed on the server.
+
+// Readlink returns the target of a symbolic link. Absolute targets inside the
+// share are rewritten relative to the link; targets outside it fail with
+// EACCES rather than exposing host paths.
+func (s *fileSystemServer) Readlink(ctx context.Context, req *pb.ReadlinkRequest) (*pb.ReadlinkResponse, error) {
+	abs, err := s.confineLink(req.Path)
+	if err != nil {
+		return &pb.ReadlinkResponse{Result: &pb.ReadlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	raw, err := s.ops.readlink(abs)
+	if err != nil {
+		return &pb.ReadlinkResponse{Result: &pb.ReadlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	target, err := s.clientTarget(abs, raw)
+	if err != nil {
+		logx.Info("symlink target outside share", "path", abs, "target", raw)
+		return &pb.ReadlinkResponse{Result: &pb.ReadlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	return &pb.ReadlinkResponse{Result: &pb.ReadlinkResponse_Target{Target: target}}, nil
+}
+
+// Symlink creates a symbolic link. Only relative targets that stay within the
+// share are accepted: an absolute target cannot mean the same thing on the
+// client and on the server.
+func (s *fileSystemServer) Symlink(ctx context.Context, req *pb.SymlinkRequest) (*pb.SymlinkResponse, error) {
+	if e := s.readOnlyError(); e != nil {
+		return &pb.SymlinkResponse{Result: &pb.SymlinkResponse_Error{Error: e}}, nil
+	}
+	abs, err := s.confineLink(req.Path)
+	if err != nil {
+		return &pb.SymlinkResponse{Result: &pb.SymlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	target, err := s.hostTarget(abs, req.Target)
+	if err != nil {
+		return &pb.SymlinkResponse{Result: &pb.SymlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	if err := s.ops.symlink(target, abs); err != nil {
+		return &pb.SymlinkResponse{Result: &pb.SymlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	fi, err := s.ops.lstat(abs)
+	if err != nil {
+		return &pb.SymlinkResponse{Result: &pb.SymlinkResponse_Error{Error: errno(err)}}, nil
+	}
+	return &pb.SymlinkResponse{Result: &pb.SymlinkResponse_Info{Info: s.toFileInfo(abs, fi)}}, nil
+}
+
+// clientTarget converts the raw target of the link at absLink to client form.
+// Relative targets are kept as written unless they pass above the share root
+// on the way, which the client could not follow.
+func (s *fileSystemServer) clientTarget(absLink, raw string) (string, error) {
+	dir := filepath.Dir(absLink)
+	relative := raw != "" && !filepath.IsAbs(raw) && filepath.VolumeName(raw) == "" && !os.IsPathSeparator(raw[0])
+
+	resolved := raw
+	switch {
+	case relative:
+		resolved = filepath.Join(dir, raw)
+	case !filepath.IsAbs(raw) && filepath.VolumeName(raw) == "" && raw != "":
+		// Rooted on the link's drive (Windows "\dir")
+		resolved = filepath.VolumeName(absLink) + raw
+	}
+	resolved = filepath.Clean(resolved)
+	if !filepath.IsAbs(resolved) || (resolved != s.root && !isSubpath(resolved, s.root)) {
+		return "", errEscapesRoot
+	}
+	if relative && s.keepsInside(dir, raw) {
+		return filepath.ToSlash(raw), nil
+	}
+	rel, err := filepath.Rel(dir, resolved)
+	if err != nil {
+		return "", errEscapesRoot
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// hostTarget validates a client-supplied target for a link at absLink and
+// returns it in host form.
+func (s *fileSystemServer) hostTarget(absLink, target string) (string, error) {
+	if target == "" {
+		return "", errEmptyTarget
+	}
+	if path.IsAbs(target) || filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return "", errAbsoluteTarget
+	}
+	if !s.keepsInside(filepath.Dir(absLink), target) {
+		return "", errEscapesRoot
+	}
+	return filepath.FromSlash(target), nil
+}
+
+// keepsInside reports whether walking the relative target from dir never
+// passes above the share root, so it resolves the same way in the mount.
+func (s *fileSystemServer) keepsInside(dir, target string) bool {
+	rel, err := filepath.Rel(s.root, dir)
+	if err != nil || startsWithDotDot(rel) {
+		return false
+	}
+	depth := 0
+	if rel != "." {
+		depth = len(strings.Split(rel, string(filepath.Separator)))
+	}
+	for _, part := range strings.Split(filepath.ToSlash(target), "/") {
+		switch part {
+		case "", ".":
+		case "..":
+			depth--
+			if depth < 0 {
+				return false
+			}
+		default:
+			depth++
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+func TestClientTarget(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	base := filepath.Base(s.root)
+	tests := []struct {
+		link    string // Share-relative
+		raw     string // Host form; absolute if it starts with root
+		want    string
+		wantErr error
+	}{
+		// Relative targets are kept as written
+		{"l", "sub/file", "sub/file", nil},
+		{"l", ".", ".", nil},
+		{"sub/l", "../file", "../file", nil},
+		{"sub/l", "a/../../file", "a/../../file", nil},
+		{"sub/l", "..", "..", nil},
+		// ... unless they pass above the root, even to come back in
+		{"sub/l", "../../" + base + "/sub/file", "file", nil},
+		{"l", "../" + base, ".", nil},
+		{"l", "..", "", errEscapesRoot},
+		{"l", "../x", "", errEscapesRoot},
+		{"sub/l", "../../x", "", errEscapesRoot},
+		{"sub/l", "../sub/../../x", "", errEscapesRoot},
+		// Absolute targets inside the root are made relative to the link
+		{"l", "root/sub/file", "sub/file", nil},
+		{"sub/l", "root/sub/file", "file", nil},
+		{"sub/deep/l", "root/file", "../../file", nil},
+		{"sub/l", "root", "..", nil},
+		{"l", "root", ".", nil},
+		// Absolute targets outside it are not exposed
+		{"l", "root/../x", "", errEscapesRoot},
+		{"l", "root-sibling/x", "", errEscapesRoot},
+		{"sub/l", "root/../" + base + "-sibling", "", errEscapesRoot},
+	}
+	for _, tt := range tests {
+		raw := filepath.FromSlash(tt.raw)
+		if rest, ok := strings.CutPrefix(tt.raw, "root"); ok {
+			raw = s.root + filepath.FromSlash(rest)
+		}
+		got, err := s.clientTarget(s.hostPath(tt.link), raw)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("clientTarget(%q, %q) = %q, %v; want %q, %v", tt.link, raw, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestHostTarget(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	base := filepath.Base(s.root)
+	tests := []struct {
+		link, target string
+		wantErr      error
+	}{
+		{"l", "sub/file", nil},
+		{"l", ".", nil},
+		{"sub/l", "..", nil},
+		{"sub/l", "../file", nil},
+		{"sub/l", "a/../../file", nil},
+		{"l", "", errEmptyTarget},
+		{"l", "/etc/passwd", errAbsoluteTarget},
+		{"sub/l", "/", errAbsoluteTarget},
+		{"l", "..", errEscapesRoot},
+		{"l", "../x", errEscapesRoot},
+		{"sub/l", "../../x", errEscapesRoot},
+		{"sub/l", "a/../../../x", errEscapesRoot},
+		// Lands inside, but only by walking above the root on the way
+		{"sub/l", "../../" + base + "/file", errEscapesRoot},
+	}
+	for _, tt := range tests {
+		got, err := s.hostTarget(s.hostPath(tt.link), tt.target)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("hostTarget(%q, %q): error %v, want %v", tt.link, tt.target, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != filepath.FromSlash(tt.target) {
+			t.Errorf("hostTarget(%q, %q) = %q", tt.link, tt.target, got)
+		}
+	}
+}
+
+func TestKeepsInside(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	tests := []struct {
+		dir, target string
+		want        bool
+	}{
+		{".", "a/b", true},
+		{".", "a/../b", true},
+		{".", "..", false},
+		{".", "a/../../b", false},
+		{"a/b", "../..", true},
+		{"a/b", "../../..", false},
+		{"a/b", "./../.././c", true},
+		{"a/b", "x//../../../c", true},
+		{"..", "x", false},
+		{"../other", "../" + filepath.Base(s.root), false},
+	}
+	for _, tt := range tests {
+		if got := s.keepsInside(s.hostPath(tt.dir), tt.target); got != tt.want {
+			t.Errorf("keepsInside(%q, %q) = %v, want %v", tt.dir, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestSymlinkRoundTrip(t *testing.T) {
+	for _, allow := range []bool{false, true} {
+		s, _ := linkShare(t, serverOptions{allowSymlinkEscape: allow})
+		ctx := context.Background()
+		for _, tt := range []struct{ link, target string }{
+			{"top", "sub/file"},
+			{"sub/back", "../sub/file"},
+			{"sub/self", "file"},
+		} {
+			resp, err := s.Symlink(ctx, &pb.SymlinkRequest{Path: tt.link, Target: tt.target})
+			if err != nil || resp.GetError() != nil {
+				t.Fatalf("allow=%v: Symlink(%q, %q): %v, %v", allow, tt.link, tt.target, err, resp.GetError())
+			}
+			if !resp.GetInfo().IsSymlink {
+				t.Errorf("allow=%v: %q not reported as a symlink", allow, tt.link)
+			}
+			rl, err := s.Readlink(ctx, &pb.ReadlinkRequest{Path: tt.link})
+			if err != nil || rl.GetTarget() != tt.target {
+				t.Errorf("allow=%v: Readlink(%q) = %q, %v, %v; want %q", allow, tt.link, rl.GetTarget(), err, rl.GetError(), tt.target)
+			}
+			data, err := os.ReadFile(s.hostPath(tt.link))
+			if err != nil || string(data) != "file" {
+				t.Errorf("allow=%v: reading through %q: %q, %v", allow, tt.link, data, err)
+			}
+		}
+
+		// Links out of the share are followed when allowed, but their
+		// targets are not exposed either way
+		for _, link := range []string{"abs-out", "rel-out"} {
+			rl, err := s.Readlink(ctx, &pb.ReadlinkRequest{Path: link})
+			if err != nil || rl.GetError().GetCode() != codeEACCES {
+				t.Errorf("allow=%v: Readlink(%q) = %q, %v; want EACCES", allow, link, rl.GetTarget(), rl.GetError())
+			}
+		}
+		for _, link := range []string{"abs-in", "rel-in", "sub/up"} {
+			rl, _ := s.Readlink(ctx, &pb.ReadlinkRequest{Path: link})
+			if rl.GetError() != nil {
+				t.Errorf("allow=%v: Readlink(%q): %v", allow, link, rl.GetError())
+			}
+		}
+	}
+}
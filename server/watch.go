@@ -2,10 +2,8 @@ package main
 
 import (
 	"context"
-	"io/fs"
 	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -14,7 +12,13 @@ import (
 	pb "github.com/example/fsdriver/proto"
 )
 
-// Watch implements the bidirectional stream for change notifications.
+// watchHeartbeatInterval is how often an otherwise idle Watch stream carries
+// a heartbeat.
+const watchHeartbeatInterval = 30 * time.Second
+
+// Watch implements the bidirectional stream for change notifications. The
+// stream subscribes through the server's shared watch hub; this goroutine
+// only drains the stream's queue, so a slow client delays nobody else.
 func (s *fileSystemServer) Watch(stream pb.FileSystemService_WatchServer) error {
 	// Get client peer information for logging
 	p, ok := peer.FromContext(stream.Context())
@@ -25,53 +29,31 @@ func (s *fileSystemServer) Watch(stream pb.FileSystemService_WatchServer) error
 
 	logx.Info("Watch stream started", "client_addr", clientAddr, "share", s.root)
 
-	// One watcher per stream
-	watcher, err := fsnotify.NewWatcher()
+	st, err := s.watches.register()
 	if err != nil {
 		logx.Error("Failed to create watcher", "client_addr", clientAddr, "error", err)
 		return err
 	}
 	defer func() {
-		watcher.Close()
+		s.watches.unregister(st)
 		logx.Info("Watch stream ended", "client_addr", clientAddr)
 	}()
 
 	ctx, cancel := context.WithCancel(stream.Context())
 	defer cancel()
 
-	var mu sync.Mutex
-	watched := make(map[string]struct{})
-
-	addPath := func(rel string, recursive bool) error {
-		abs, err := s.confine(rel)
-		if err != nil {
-			return err
-		}
-		// Add directory itself
-		if _, ok := watched[abs]; !ok {
-			if err := watcher.Add(abs); err != nil {
-				return err
-			}
-			watched[abs] = struct{}{}
-		}
-		if !recursive {
-			return nil
+	// Replies go through the queue too, so a subscription's ack precedes its
+	// events. They wait for room rather than being dropped.
+	reply := func(msg *pb.WatchEvent) {
+		select {
+		case st.queue <- msg:
+		case <-ctx.Done():
 		}
-		// Walk subdirectories using WalkDir
-		return filepath.WalkDir(abs, func(p string, d fs.DirEntry, walkErr error) error {
-			if walkErr != nil {
-				return nil
-			}
-			if !d.IsDir() {
-				return nil
-			}
-			if _, ok := watched[p]; ok {
-				return nil
-			}
-			if err := watcher.Add(p); err == nil {
-				watched[p] = struct{}{}
-			}
-			return nil
+	}
+	replyError := func(id uint64, path string, err error) {
+		reply(&pb.WatchEvent{
+			Timestamp: time.Now().Unix(),
+			Control:   &pb.WatchEvent_Error{Error: &pb.WatchError{SubscriptionId: id, Path: path, Error: errno(err)}},
 		})
 	}
 
@@ -86,76 +68,85 @@ func (s *fileSystemServer) Watch(stream pb.FileSystemService_WatchServer) error
 				return
 			}
 
+			if req.Op == pb.WatchOperation_UNSUBSCRIBE {
+				if !s.watches.unsubscribe(st, req.SubscriptionId) {
+					replyError(req.SubscriptionId, "", errUnknownSub)
+					continue
+				}
+				logx.Info("Watch subscription removed", "client_addr", clientAddr, "subscription_id", req.SubscriptionId)
+				reply(&pb.WatchEvent{
+					Timestamp: time.Now().Unix(),
+					Control:   &pb.WatchEvent_Ack{Ack: &pb.WatchAck{SubscriptionId: req.SubscriptionId, Unsubscribed: true}},
+				})
+				continue
+			}
+
 			logx.Info("Watch request received",
 				"client_addr", clientAddr,
 				"path", req.Path,
-				"recursive", req.Recursive)
+				"recursive", req.Recursive,
+				"event_types", req.EventTypes,
+				"include", req.Include,
+				"exclude", req.Exclude)
 
-			mu.Lock()
-			e := addPath(req.Path, req.Recursive)
-			mu.Unlock()
+			id, e := s.watches.subscribe(st, req)
 			if e != nil {
 				logx.Error("Failed to add watch path",
 					"client_addr", clientAddr,
 					"path", req.Path,
 					"error", e)
-				// Report an ATTRIB with error context using old_path field for details
-				_ = stream.Send(&pb.WatchEvent{
-					Path:      sanitizeRel(req.Path),
-					Type:      pb.WatchEventType_ATTRIB,
-					OldPath:   "error: " + e.Error(),
-					Timestamp: time.Now().Unix(),
-				})
+				replyError(id, sanitizeRel(req.Path), e)
 			} else {
 				logx.Info("Watch path added successfully",
 					"client_addr", clientAddr,
 					"path", req.Path,
-					"recursive", req.Recursive)
+					"recursive", req.Recursive,
+					"subscription_id", id)
+				reply(&pb.WatchEvent{
+					Timestamp: time.Now().Unix(),
+					Control: &pb.WatchEvent_Ack{Ack: &pb.WatchAck{
+						SubscriptionId:      id,
+						Path:                sanitizeRel(req.Path),
+						HeartbeatIntervalMs: int32(watchHeartbeatInterval / time.Millisecond),
+					}},
+				})
 			}
 		}
 	}()
 
-	// Event loop
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// Send loop
 	for {
+		var msg *pb.WatchEvent
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case err := <-recvErrCh:
 			return err
-		case ev := <-watcher.Events:
-			// Map event
-			evtType := mapFsnotifyEvent(ev)
-			if evtType == pb.WatchEventType_UNKNOWN {
+		case <-st.wake:
+			if !st.overflowed.Swap(false) {
 				continue
 			}
-			// Compute path relative to share root
-			rel := ev.Name
-			if strings.HasPrefix(rel, s.root) {
-				if r, err := filepath.Rel(s.root, rel); err == nil {
-					rel = filepath.ToSlash(r)
-				}
-			}
-			_ = stream.Send(&pb.WatchEvent{
-				Path:      rel,
-				Type:      evtType,
+			logx.Error("Watch events lost", "client_addr", clientAddr)
+			msg = &pb.WatchEvent{
 				Timestamp: time.Now().Unix(),
-			})
-			// On create of directory when recursive, attempt to add watcher
-			if (ev.Op & fsnotify.Create) != 0 {
-				// Best-effort add
-				mu.Lock()
-				_ = addPath(rel, true)
-				mu.Unlock()
+				Control:   &pb.WatchEvent_Overflow{Overflow: &pb.WatchOverflow{}},
 			}
-		case err := <-watcher.Errors:
-			// Surface watcher errors as ATTRIB with details
-			_ = stream.Send(&pb.WatchEvent{
-				Path:      "",
-				Type:      pb.WatchEventType_ATTRIB,
-				OldPath:   "watch-error: " + err.Error(),
+		case msg = <-st.queue:
+		case <-heartbeat.C:
+			msg = &pb.WatchEvent{
 				Timestamp: time.Now().Unix(),
-			})
+				Control:   &pb.WatchEvent_Heartbeat{Heartbeat: &pb.WatchHeartbeat{}},
+			}
 		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+		// Any message proves the stream alive, so the next heartbeat is
+		// due a full interval after it
+		heartbeat.Reset(watchHeartbeatInterval)
 	}
 }
 
@@ -174,6 +165,22 @@ func mapFsnotifyEvent(ev fsnotify.Event) pb.WatchEventType {
 	}
 }
 
+// shareRel converts an absolute path below the share root to the
+// share-relative, '/'-separated form used in events.
+func (s *fileSystemServer) shareRel(abs string) string {
+	if strings.HasPrefix(abs, s.root) {
+		if r, err := filepath.Rel(s.root, abs); err == nil {
+			return filepath.ToSlash(r)
+		}
+	}
+	return abs
+}
+
+// hostPath is the inverse of shareRel.
+func (s *fileSystemServer) hostPath(rel string) string {
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}
+
 func sanitizeRel(p string) string {
 	return filepath.ToSlash(filepath.Clean(p))
 }
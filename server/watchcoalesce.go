@@ -0,0 +1,148 @@
+package main
+
+import (
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// coalescer merges the bursts of events editors produce when saving (write
+// to a temporary file, rename, delete, create) into one event per path.
+// An event is held for the window after the first event for its path and
+// then released; events for different paths leave in the order their first
+// event arrived.
+//
+// The coalescer does no timing itself: callers pass the current time, which
+// keeps it deterministic.
+type coalescer struct {
+	window  time.Duration
+	queue   []*pendingEvent          // In order of first arrival
+	pending map[string]*pendingEvent // Queued events by path
+}
+
+type pendingEvent struct {
+	event   *pb.WatchEvent
+	due     time.Time
+	dropped bool // Cancelled out; skipped unless a later event revives it
+
+	// What happened to the path within the window. Whether it existed when
+	// the window opened is inferred from the first event: anything but
+	// CREATE means it did.
+	existed  bool
+	exists   bool
+	replaced bool              // Went away and came back
+	gone     pb.WatchEventType // How it went away: DELETE or RENAME
+	modified bool
+}
+
+func newPendingEvent(ev *pb.WatchEvent, due time.Time) *pendingEvent {
+	existed := ev.Type != pb.WatchEventType_CREATE
+	p := &pendingEvent{event: ev, due: due, existed: existed, exists: existed}
+	p.apply(ev)
+	return p
+}
+
+// apply folds ev into the pending event. A path that did not exist before
+// the window and does not exist after it is dropped; one that existed on
+// both ends and was replaced in between is reported as created, so the
+// client looks it up again.
+func (p *pendingEvent) apply(ev *pb.WatchEvent) {
+	switch ev.Type {
+	case pb.WatchEventType_DELETE, pb.WatchEventType_RENAME:
+		p.exists = false
+		p.gone = ev.Type
+	default:
+		// Changes to a path that is gone also mean it is back; its CREATE
+		// was missed
+		p.modified = p.modified || ev.Type == pb.WatchEventType_MODIFY
+		p.appear()
+	}
+	p.event.Timestamp = ev.Timestamp
+
+	p.dropped = false
+	switch {
+	case !p.existed && !p.exists:
+		p.dropped = true
+	case !p.existed:
+		p.event.Type = pb.WatchEventType_CREATE
+	case !p.exists:
+		p.event.Type = p.gone
+	case p.replaced:
+		p.event.Type = pb.WatchEventType_CREATE
+	case p.modified:
+		p.event.Type = pb.WatchEventType_MODIFY
+	default:
+		p.event.Type = pb.WatchEventType_ATTRIB
+	}
+}
+
+func (p *pendingEvent) appear() {
+	if !p.exists && p.existed {
+		p.replaced = true
+	}
+	p.exists = true
+}
+
+func newCoalescer(window time.Duration) *coalescer {
+	return &coalescer{window: window, pending: make(map[string]*pendingEvent)}
+}
+
+// add queues ev, merging it with a pending event for the same path.
+func (c *coalescer) add(ev *pb.WatchEvent, now time.Time) {
+	if ev.Type == pb.WatchEventType_RENAME && ev.OldPath != "" {
+		c.addRename(ev, now)
+		return
+	}
+	if p, ok := c.pending[ev.Path]; ok {
+		p.apply(ev)
+		return
+	}
+	p := newPendingEvent(ev, now.Add(c.window))
+	c.queue = append(c.queue, p)
+	c.pending[ev.Path] = p
+}
+
+// addRename queues a paired rename. A file created and then moved within
+// the window, as editors do with temporary files, is reported as created
+// under its new name. Otherwise the rename is queued on its own, and neither
+// name merges events across it, so the client sees them in order.
+func (c *coalescer) addRename(ev *pb.WatchEvent, now time.Time) {
+	if p, ok := c.pending[ev.OldPath]; ok && !p.existed && p.exists {
+		p.exists = false
+		p.dropped = true
+		c.add(&pb.WatchEvent{Path: ev.Path, Type: pb.WatchEventType_CREATE, Timestamp: ev.Timestamp}, now)
+		return
+	}
+	delete(c.pending, ev.OldPath)
+	delete(c.pending, ev.Path)
+	c.queue = append(c.queue, &pendingEvent{event: ev, due: now.Add(c.window)})
+}
+
+// flush returns the events due at now, in order. Events behind one that is
+// not yet due wait for it, so ordering across paths is preserved.
+func (c *coalescer) flush(now time.Time) []*pb.WatchEvent {
+	var out []*pb.WatchEvent
+	for len(c.queue) > 0 {
+		p := c.queue[0]
+		if p.due.After(now) {
+			break
+		}
+		if !p.dropped {
+			out = append(out, p.event)
+		}
+		if c.pending[p.event.Path] == p {
+			delete(c.pending, p.event.Path)
+		}
+		c.queue[0] = nil
+		c.queue = c.queue[1:]
+	}
+	return out
+}
+
+// next returns when the oldest queued event is due.
+func (c *coalescer) next() (time.Time, bool) {
+	if len(c.queue) == 0 {
+		return time.Time{}, false
+	}
+	return c.queue[0].due, true
+}
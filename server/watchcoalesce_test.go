@@ -0,0 +1,164 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+const (
+	evCreate = pb.WatchEventType_CREATE
+	evModify = pb.WatchEventType_MODIFY
+	evDelete = pb.WatchEventType_DELETE
+	evRename = pb.WatchEventType_RENAME
+	evAttrib = pb.WatchEventType_ATTRIB
+)
+
+var t0 = time.Unix(1700000000, 0)
+
+func event(typ pb.WatchEventType, path string) *pb.WatchEvent {
+	return &pb.WatchEvent{Path: path, Type: typ}
+}
+
+func rename(oldPath, newPath string) *pb.WatchEvent {
+	return &pb.WatchEvent{Path: newPath, OldPath: oldPath, Type: evRename}
+}
+
+// describe renders events as "TYPE path" or "RENAME old>new" for comparison.
+func describe(events []*pb.WatchEvent) []string {
+	out := make([]string, 0, len(events))
+	for _, ev := range events {
+		s := ev.Type.String() + " "
+		if ev.OldPath != "" {
+			s += ev.OldPath + ">"
+		}
+		out = append(out, s+ev.Path)
+	}
+	return out
+}
+
+func TestCoalescerMergesPerPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []*pb.WatchEvent
+		want   []string
+	}{
+		{"single modify", []*pb.WatchEvent{event(evModify, "a")}, []string{"MODIFY a"}},
+		{"modify after attrib", []*pb.WatchEvent{event(evAttrib, "a"), event(evModify, "a")}, []string{"MODIFY a"}},
+		{"attrib after modify", []*pb.WatchEvent{event(evModify, "a"), event(evAttrib, "a")}, []string{"MODIFY a"}},
+		{"create and write", []*pb.WatchEvent{event(evCreate, "a"), event(evModify, "a"), event(evAttrib, "a")}, []string{"CREATE a"}},
+		{"create and delete", []*pb.WatchEvent{event(evCreate, "a"), event(evModify, "a"), event(evDelete, "a")}, nil},
+		{"create, delete, create", []*pb.WatchEvent{event(evCreate, "a"), event(evDelete, "a"), event(evCreate, "a")}, []string{"CREATE a"}},
+		{"delete and recreate", []*pb.WatchEvent{event(evDelete, "a"), event(evCreate, "a")}, []string{"CREATE a"}},
+		{"delete, create, delete", []*pb.WatchEvent{event(evDelete, "a"), event(evCreate, "a"), event(evDelete, "a")}, []string{"DELETE a"}},
+		{"modify then delete", []*pb.WatchEvent{event(evModify, "a"), event(evDelete, "a")}, []string{"DELETE a"}},
+		{"unpaired rename out", []*pb.WatchEvent{event(evModify, "a"), event(evRename, "a")}, []string{"RENAME a"}},
+		{"modify after missed create", []*pb.WatchEvent{event(evDelete, "a"), event(evModify, "a")}, []string{"CREATE a"}},
+		{"temporary file renamed", []*pb.WatchEvent{event(evCreate, "a.tmp"), event(evModify, "a.tmp"), rename("a.tmp", "a")}, []string{"CREATE a"}},
+		{"temporary file renamed, then deleted", []*pb.WatchEvent{event(evCreate, "a.tmp"), rename("a.tmp", "a"), event(evDelete, "a")}, nil},
+		{"rename of existing file", []*pb.WatchEvent{event(evModify, "a"), rename("a", "b"), event(evModify, "b")}, []string{"MODIFY a", "RENAME a>b", "MODIFY b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCoalescer(100 * time.Millisecond)
+			for i, ev := range tt.events {
+				c.add(ev, t0.Add(time.Duration(i)*time.Millisecond))
+			}
+			if got := describe(c.flush(t0.Add(time.Second))); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if _, ok := c.next(); ok {
+				t.Error("events left after flushing everything")
+			}
+		})
+	}
+}
+
+func TestCoalescerKeepsOrderAcrossPaths(t *testing.T) {
+	ms := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Millisecond) }
+	c := newCoalescer(100 * time.Millisecond)
+	c.add(event(evModify, "a"), ms(0))
+	c.add(event(evCreate, "b"), ms(10))
+	c.add(event(evDelete, "c"), ms(20))
+	c.add(event(evAttrib, "a"), ms(30))
+
+	check := func(at int, want ...string) {
+		t.Helper()
+		if got := describe(c.flush(ms(at))); !slices.Equal(got, want) {
+			t.Errorf("flush at %dms: got %q, want %q", at, got, want)
+		}
+	}
+	check(50)
+	check(105, "MODIFY a")
+	c.add(event(evModify, "b"), ms(107))
+	check(120, "CREATE b", "DELETE c")
+
+	// Its window has closed, so this is a new event for a, queued after d
+	c.add(event(evCreate, "d"), ms(130))
+	c.add(event(evAttrib, "a"), ms(140))
+	if due, ok := c.next(); !ok || !due.Equal(ms(230)) {
+		t.Errorf("next = %v, %v; want %v", due, ok, ms(230))
+	}
+	check(235, "CREATE d")
+	check(240, "ATTRIB a")
+}
+
+func TestCoalescerRenameHoldsLaterEvents(t *testing.T) {
+	c := newCoalescer(100 * time.Millisecond)
+	c.add(rename("a", "b"), t0)
+	c.add(event(evModify, "z"), t0.Add(10*time.Millisecond))
+
+	// The rename is due first, and z waits behind it
+	if due, ok := c.next(); !ok || !due.Equal(t0.Add(100*time.Millisecond)) {
+		t.Fatalf("next = %v, %v; want %v", due, ok, t0.Add(100*time.Millisecond))
+	}
+	if got := describe(c.flush(t0.Add(100 * time.Millisecond))); !slices.Equal(got, []string{"RENAME a>b"}) {
+		t.Errorf("got %q", got)
+	}
+	if due, ok := c.next(); !ok || !due.Equal(t0.Add(110*time.Millisecond)) {
+		t.Fatalf("next = %v, %v; want %v", due, ok, t0.Add(110*time.Millisecond))
+	}
+	if got := describe(c.flush(t0.Add(110 * time.Millisecond))); !slices.Equal(got, []string{"MODIFY z"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRenamePairer(t *testing.T) {
+	// a was moved to b; c and d are different files.
+	moved := map[string]string{"a": "b"}
+	sameFile := func(oldPath, newPath string) bool { return moved[oldPath] == newPath }
+
+	tests := []struct {
+		name   string
+		events []*pb.WatchEvent
+		// expireAt is when expire is called after the events, relative to
+		// the last one
+		expireAt time.Duration
+		want     []string
+	}{
+		{"confirmed pair", []*pb.WatchEvent{event(evRename, "a"), event(evCreate, "b")}, 0, []string{"RENAME a>b"}},
+		{"different file", []*pb.WatchEvent{event(evRename, "c"), event(evCreate, "d")}, 0, []string{"DELETE c", "CREATE d"}},
+		{"unknown file", []*pb.WatchEvent{event(evRename, "x"), event(evCreate, "b")}, 0, []string{"DELETE x", "CREATE b"}},
+		{"other event in between", []*pb.WatchEvent{event(evRename, "a"), event(evModify, "z"), event(evCreate, "b")}, 0, []string{"DELETE a", "MODIFY z", "CREATE b"}},
+		{"moved out, not yet expired", []*pb.WatchEvent{event(evRename, "a")}, renamePairWindow - time.Millisecond, nil},
+		{"moved out", []*pb.WatchEvent{event(evRename, "a")}, renamePairWindow, []string{"DELETE a"}},
+		{"two renames", []*pb.WatchEvent{event(evRename, "c"), event(evRename, "a"), event(evCreate, "b")}, 0, []string{"DELETE c", "RENAME a>b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := renamePairer{sameFile: sameFile}
+			var out []*pb.WatchEvent
+			now := t0
+			for _, ev := range tt.events {
+				out = append(out, r.push(ev, now)...)
+				now = now.Add(time.Millisecond)
+			}
+			out = append(out, r.expire(now.Add(-time.Millisecond+tt.expireAt))...)
+			if got := describe(out); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
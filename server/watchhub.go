@@ -0,0 +1,394 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// watchQueueSize bounds the messages buffered for one Watch stream. A client
+// that falls further behind loses changes and is sent an overflow notice.
+const watchQueueSize = 4096
+
+// watchHub owns the server's single fsnotify watcher. Each directory is
+// watched once, with a count of the subscriptions that need it, however many
+// streams subscribe to it; changes are fanned out to the streams whose
+// subscriptions match. Fan-out never blocks on a stream.
+type watchHub struct {
+	s        *fileSystemServer
+	start    sync.Once
+	startErr error
+	watcher  *fsnotify.Watcher
+
+	mu      sync.Mutex
+	refs    map[string]int // Watched directories by number of subscriptions holding them
+	streams map[*watchStream]struct{}
+}
+
+// watchStream is the hub's side of one Watch RPC.
+type watchStream struct {
+	queue      chan *pb.WatchEvent
+	wake       chan struct{} // Signalled when overflowed is set
+	overflowed atomic.Bool
+	lastID     uint64
+	subs       map[uint64]*hubSubscription // Guarded by watchHub.mu
+}
+
+// hubSubscription is a subscription and the directories it holds.
+type hubSubscription struct {
+	*watchSubscription
+	dirs     map[string]struct{}
+	released bool // Unsubscribed; walks still running must not hold more
+}
+
+func newWatchHub(s *fileSystemServer) *watchHub {
+	return &watchHub{
+		s:       s,
+		refs:    make(map[string]int),
+		streams: make(map[*watchStream]struct{}),
+	}
+}
+
+// register adds a stream; the watcher is started with the first one.
+func (h *watchHub) register() (*watchStream, error) {
+	h.start.Do(func() {
+		h.watcher, h.startErr = fsnotify.NewWatcher()
+		if h.startErr == nil {
+			go h.run()
+		}
+	})
+	if h.startErr != nil {
+		return nil, h.startErr
+	}
+	st := &watchStream{
+		queue: make(chan *pb.WatchEvent, watchQueueSize),
+		wake:  make(chan struct{}, 1),
+		subs:  make(map[uint64]*hubSubscription),
+	}
+	h.mu.Lock()
+	h.streams[st] = struct{}{}
+	h.mu.Unlock()
+	return st, nil
+}
+
+// unregister drops a stream and releases everything its subscriptions hold.
+func (h *watchHub) unregister(st *watchStream) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, sub := range st.subs {
+		h.release(sub)
+	}
+	delete(h.streams, st)
+}
+
+// subscribe adds a subscription to st and starts watching what it covers.
+// Directories below a recursive subscription are found without holding the
+// hub lock, so a large tree does not stall the delivery of other changes.
+func (h *watchHub) subscribe(st *watchStream, req *pb.WatchRequest) (uint64, error) {
+	h.mu.Lock()
+	st.lastID++
+	id := st.lastID
+	h.mu.Unlock()
+	ws, err := newWatchSubscription(id, req)
+	if err != nil {
+		return id, err
+	}
+	if _, err := h.s.confine(req.Path); err != nil {
+		return id, err
+	}
+	// Watched under the name the client used, so that events carry paths
+	// the subscription covers
+	abs := h.s.hostPath(ws.path)
+	sub := &hubSubscription{watchSubscription: ws, dirs: make(map[string]struct{})}
+	h.mu.Lock()
+	if err := h.acquire(sub, abs); err != nil {
+		h.mu.Unlock()
+		return id, err
+	}
+	st.subs[id] = sub
+	h.mu.Unlock()
+	if sub.recursive {
+		h.holdBelow(sub, abs)
+	}
+	return id, nil
+}
+
+// unsubscribe removes subscription id from st; false if there is none.
+func (h *watchHub) unsubscribe(st *watchStream, id uint64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sub, ok := st.subs[id]
+	if !ok {
+		return false
+	}
+	h.release(sub)
+	delete(st.subs, id)
+	return true
+}
+
+// holdBelow watches every directory below abs for sub, skipping excluded
+// trees. The hub lock is only taken to add each directory, and the walk
+// stops once sub has been released. Failures are ignored: the directory may
+// be gone already.
+func (h *watchHub) holdBelow(sub *hubSubscription, abs string) {
+	_ = filepath.WalkDir(abs, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || !d.IsDir() || p == abs {
+			return nil
+		}
+		if sub.excluded(h.s.shareRel(p)) {
+			return filepath.SkipDir
+		}
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if sub.released {
+			return filepath.SkipAll
+		}
+		_ = h.acquire(sub, p)
+		return nil
+	})
+}
+
+func (h *watchHub) acquire(sub *hubSubscription, abs string) error {
+	if _, ok := sub.dirs[abs]; ok {
+		return nil
+	}
+	if h.refs[abs] == 0 {
+		if err := h.watcher.Add(abs); err != nil {
+			return err
+		}
+	}
+	h.refs[abs]++
+	sub.dirs[abs] = struct{}{}
+	return nil
+}
+
+// release drops every directory sub holds, unwatching those no other
+// subscription needs.
+func (h *watchHub) release(sub *hubSubscription) {
+	for abs := range sub.dirs {
+		h.refs[abs]--
+		if h.refs[abs] <= 0 {
+			delete(h.refs, abs)
+			_ = h.watcher.Remove(abs)
+		}
+	}
+	clear(sub.dirs)
+	sub.released = true
+}
+
+// forgetTree drops abs and the directories below it after it was deleted or
+// moved; fsnotify has already removed its watch. A directory moved within
+// the share is watched again when its new name is reported as created.
+func (h *watchHub) forgetTree(abs string) {
+	if _, ok := h.refs[abs]; !ok {
+		return
+	}
+	prefix := abs + string(filepath.Separator)
+	for dir := range h.refs {
+		if dir != abs && !strings.HasPrefix(dir, prefix) {
+			continue
+		}
+		delete(h.refs, dir)
+		_ = h.watcher.Remove(dir)
+		for st := range h.streams {
+			for _, sub := range st.subs {
+				delete(sub.dirs, dir)
+			}
+		}
+	}
+}
+
+// run turns raw fsnotify events into paired, coalesced changes and fans them
+// out.
+func (h *watchHub) run() {
+	renames := renamePairer{sameFile: h.sameFile}
+	co := newCoalescer(h.s.opts.watchDebounce)
+	flushTimer := time.NewTimer(time.Hour)
+	flushTimer.Stop()
+	schedule := func() {
+		due, ok := co.next()
+		if rdue, rok := renames.next(); rok && (!ok || rdue.Before(due)) {
+			due, ok = rdue, true
+		}
+		if ok {
+			flushTimer.Reset(time.Until(due))
+		}
+	}
+	forward := func(events []*pb.WatchEvent, now time.Time) {
+		for _, ev := range events {
+			if h.s.opts.watchDebounce > 0 {
+				co.add(ev, now)
+			} else {
+				h.dispatch(ev)
+			}
+		}
+	}
+
+	for {
+		select {
+		case now := <-flushTimer.C:
+			forward(renames.expire(now), now)
+			for _, ev := range co.flush(now) {
+				h.dispatch(ev)
+			}
+			schedule()
+		case ev, ok := <-h.watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				h.mu.Lock()
+				h.forgetTree(ev.Name)
+				h.mu.Unlock()
+			}
+			if ev.Op&fsnotify.Remove != 0 {
+				h.s.ids.forget(ev.Name)
+			}
+			var created os.FileInfo
+			if ev.Op&fsnotify.Create != 0 {
+				if fi, err := os.Lstat(ev.Name); err == nil {
+					dev, ino, _ := fileID(ev.Name, fi)
+					h.s.ids.record(ev.Name, fileKey{dev, ino})
+					created = fi
+				}
+			}
+			evtType := mapFsnotifyEvent(ev)
+			if evtType == pb.WatchEventType_UNKNOWN {
+				continue
+			}
+			rel := h.s.shareRel(ev.Name)
+			now := time.Now()
+			out := &pb.WatchEvent{
+				Path:      rel,
+				Type:      evtType,
+				Timestamp: now.Unix(),
+			}
+			forward(renames.push(out, now), now)
+			schedule()
+			// Recursive subscriptions extend to new directories
+			if created != nil && created.IsDir() {
+				h.holdNewDir(ev.Name, rel)
+			}
+		case err, ok := <-h.watcher.Errors:
+			if !ok {
+				return
+			}
+			h.broadcastError(err)
+		}
+	}
+}
+
+// sameFile reports whether the entry at newPath is the file last seen at
+// oldPath, and if so moves the record to its new name.
+func (h *watchHub) sameFile(oldPath, newPath string) bool {
+	oldAbs, newAbs := h.s.hostPath(oldPath), h.s.hostPath(newPath)
+	want, ok := h.s.ids.lookup(oldAbs)
+	if !ok {
+		return false
+	}
+	fi, err := os.Lstat(newAbs)
+	if err != nil {
+		return false
+	}
+	if dev, ino, _ := fileID(newAbs, fi); (fileKey{dev, ino}) != want {
+		return false
+	}
+	h.s.ids.forget(oldAbs)
+	h.s.ids.record(newAbs, want)
+	return true
+}
+
+// holdNewDir extends the recursive subscriptions covering a new directory
+// to it. Directories inside it, as when a tree is moved in, are added in the
+// background so run keeps draining events meanwhile.
+func (h *watchHub) holdNewDir(abs, rel string) {
+	var subs []*hubSubscription
+	h.mu.Lock()
+	for st := range h.streams {
+		for _, sub := range st.subs {
+			if sub.recursive && sub.covers(rel) && !sub.excluded(rel) && h.acquire(sub, abs) == nil {
+				subs = append(subs, sub)
+			}
+		}
+	}
+	h.mu.Unlock()
+	if len(subs) > 0 {
+		go func() {
+			for _, sub := range subs {
+				h.holdBelow(sub, abs)
+			}
+		}()
+	}
+}
+
+// dispatch queues a change for every stream with a matching subscription,
+// tagged with that stream's subscription IDs.
+func (h *watchHub) dispatch(ev *pb.WatchEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for st := range h.streams {
+		var ids []uint64
+		for id, sub := range st.subs {
+			if sub.matches(ev) {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		slices.Sort(ids)
+		st.offer(&pb.WatchEvent{
+			Path:            ev.Path,
+			Type:            ev.Type,
+			OldPath:         ev.OldPath,
+			Timestamp:       ev.Timestamp,
+			SubscriptionIds: ids,
+		})
+	}
+}
+
+// broadcastError reports a watcher error to every stream. Lost kernel events
+// become overflow notices.
+func (h *watchHub) broadcastError(err error) {
+	overflow := errors.Is(err, fsnotify.ErrEventOverflow)
+	logx.Error("Watcher error", "error", err, "overflow", overflow)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for st := range h.streams {
+		if overflow {
+			st.markOverflow()
+			continue
+		}
+		st.offer(&pb.WatchEvent{
+			Timestamp: time.Now().Unix(),
+			Control:   &pb.WatchEvent_Error{Error: &pb.WatchError{Error: errno(err)}},
+		})
+	}
+}
+
+// offer queues msg without blocking; a full queue marks the stream overflowed.
+func (st *watchStream) offer(msg *pb.WatchEvent) {
+	select {
+	case st.queue <- msg:
+	default:
+		st.markOverflow()
+	}
+}
+
+func (st *watchStream) markOverflow() {
+	st.overflowed.Store(true)
+	select {
+	case st.wake <- struct{}{}:
+	default:
+	}
+}
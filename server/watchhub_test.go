@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// watchedDirs lists the directories the hub watches, share-relative.
+func watchedDirs(h *watchHub) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []string
+	for abs := range h.refs {
+		out = append(out, h.s.shareRel(abs))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestWatchHubHoldsAndReleasesTree(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	mkdirs(t, s, "a/b/c", "a/d", "e")
+	h := s.watches
+	st, err := h.register()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.unregister(st)
+
+	rec, err := h.subscribe(st, &pb.WatchRequest{Path: "a", Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flat, err := h.subscribe(st, &pb.WatchRequest{Path: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := watchedDirs(h), []string{"a", "a/b", "a/b/c", "a/d"}; !slices.Equal(got, want) {
+		t.Fatalf("watched %q, want %q", got, want)
+	}
+
+	h.unsubscribe(st, rec)
+	if got, want := watchedDirs(h), []string{"a"}; !slices.Equal(got, want) {
+		t.Fatalf("after unsubscribe watched %q, want %q", got, want)
+	}
+	h.unsubscribe(st, flat)
+	if got := watchedDirs(h); len(got) != 0 {
+		t.Fatalf("after unsubscribing all watched %q", got)
+	}
+}
+
+func TestWatchHubReleasedSubscriptionStopsWalk(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	mkdirs(t, s, "a/b", "a/c")
+	h := s.watches
+	if _, err := h.register(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A walk that finishes after its subscription was dropped adds nothing
+	sub := &hubSubscription{
+		watchSubscription: &watchSubscription{path: "a", recursive: true},
+		dirs:              make(map[string]struct{}),
+	}
+	h.mu.Lock()
+	h.release(sub)
+	h.mu.Unlock()
+	h.holdBelow(sub, filepath.Join(s.root, "a"))
+	if got := watchedDirs(h); len(got) != 0 {
+		t.Fatalf("released subscription still watches %q", got)
+	}
+}
+
+func TestWatchHubExtendsToNewDirectories(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	mkdirs(t, s, "a")
+	h := s.watches
+	st, err := h.register()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.unregister(st)
+	if _, err := h.subscribe(st, &pb.WatchRequest{Path: "a", Recursive: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A tree moved in at once is picked up by the background walk
+	staging := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(staging, "n", "x", "y"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(staging, "n"), filepath.Join(s.root, "a", "n")); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a", "a/n", "a/n/x", "a/n/x/y"}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got := watchedDirs(h)
+		if slices.Equal(got, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("watched %q, want %q", got, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchHubSkipsExcludedDirectories(t *testing.T) {
+	s := newTestServer(t, serverOptions{})
+	mkdirs(t, s, "a/b", "a/node_modules/x/y", "a/b/.git/objects")
+	h := s.watches
+	st, err := h.register()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.unregister(st)
+	req := &pb.WatchRequest{Path: "a", Recursive: true, Exclude: []string{"**/node_modules", "**/.git/**"}}
+	if _, err := h.subscribe(st, req); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := watchedDirs(h), []string{"a", "a/b"}; !slices.Equal(got, want) {
+		t.Fatalf("watched %q, want %q", got, want)
+	}
+
+	// Nor are excluded directories created later
+	mkdirs(t, s, "a/b/node_modules")
+	h.holdNewDir(filepath.Join(s.root, "a", "b", "node_modules"), "a/b/node_modules")
+	if got, want := watchedDirs(h), []string{"a", "a/b"}; !slices.Equal(got, want) {
+		t.Fatalf("after a new excluded directory watched %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"time"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// renamePairWindow is how long the old name of a rename waits for its new
+// name. Both backends report the halves back to back, so a missing partner
+// means the entry was moved out of the watched tree.
+const renamePairWindow = 50 * time.Millisecond
+
+// renamePairer joins the two events fsnotify reports for a rename, RENAME
+// for the old name followed by CREATE for the new one, into a single RENAME
+// carrying both paths. fsnotify gives no rename cookie, so the pair is only
+// trusted if sameFile confirms the new name is the file last seen under the
+// old one. A RENAME that is not directly followed by its CREATE falls back to
+// DELETE of the old name; the CREATE then stands on its own.
+type renamePairer struct {
+	sameFile func(oldPath, newPath string) bool
+	old      *pb.WatchEvent // RENAME waiting for its partner
+	due      time.Time
+}
+
+// push returns the events to forward after ev, in order.
+func (r *renamePairer) push(ev *pb.WatchEvent, now time.Time) []*pb.WatchEvent {
+	var out []*pb.WatchEvent
+	if r.old != nil {
+		if ev.Type == pb.WatchEventType_CREATE && r.sameFile(r.old.Path, ev.Path) {
+			ev.Type = pb.WatchEventType_RENAME
+			ev.OldPath = r.old.Path
+			r.old = nil
+			return append(out, ev)
+		}
+		out = append(out, r.unpaired())
+	}
+	if ev.Type == pb.WatchEventType_RENAME {
+		r.old = ev
+		r.due = now.Add(renamePairWindow)
+		return out
+	}
+	return append(out, ev)
+}
+
+// expire returns the waiting rename as a DELETE once its window has passed.
+func (r *renamePairer) expire(now time.Time) []*pb.WatchEvent {
+	if r.old == nil || r.due.After(now) {
+		return nil
+	}
+	return []*pb.WatchEvent{r.unpaired()}
+}
+
+// next returns when the waiting rename expires.
+func (r *renamePairer) next() (time.Time, bool) {
+	return r.due, r.old != nil
+}
+
+func (r *renamePairer) unpaired() *pb.WatchEvent {
+	ev := r.old
+	r.old = nil
+	ev.Type = pb.WatchEventType_DELETE
+	return ev
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"path"
+	"strings"
+
+	pb "github.com/example/fsdriver/proto"
+)
+
+// watchSubscription is one SUBSCRIBE of a Watch stream: a directory, and
+// filters deciding which changes below it the client wants.
+type watchSubscription struct {
+	id        uint64
+	path      string // Share-relative, '/'-separated; "." is the root
+	recursive bool
+	types     map[pb.WatchEventType]bool // nil = all
+	include   []string
+	exclude   []string
+}
+
+func newWatchSubscription(id uint64, req *pb.WatchRequest) (*watchSubscription, error) {
+	sub := &watchSubscription{
+		id:        id,
+		path:      sanitizeRel(req.Path),
+		recursive: req.Recursive,
+		include:   req.Include,
+		exclude:   req.Exclude,
+	}
+	for _, pattern := range append(append([]string(nil), req.Include...), req.Exclude...) {
+		if !validGlob(pattern) {
+			return nil, errInvalidGlob
+		}
+	}
+	if len(req.EventTypes) > 0 {
+		sub.types = make(map[pb.WatchEventType]bool, len(req.EventTypes))
+		for _, t := range req.EventTypes {
+			sub.types[t] = true
+		}
+	}
+	return sub, nil
+}
+
+// matches reports whether a change event is wanted by the subscription. A
+// rename matches if either of its paths does.
+func (w *watchSubscription) matches(ev *pb.WatchEvent) bool {
+	if w.types != nil && !w.types[ev.Type] {
+		return false
+	}
+	if w.matchesPath(ev.Path) {
+		return true
+	}
+	return ev.OldPath != "" && w.matchesPath(ev.OldPath)
+}
+
+func (w *watchSubscription) matchesPath(p string) bool {
+	if !w.covers(p) && !w.covers(path.Dir(p)) {
+		return false
+	}
+	if w.excluded(p) {
+		return false
+	}
+	if len(w.include) == 0 {
+		return true
+	}
+	for _, pattern := range w.include {
+		if matchGlob(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether p, or a directory between it and the subscribed
+// one, matches an exclude pattern. Excluded directories are not watched, so
+// nothing below them is reported either.
+func (w *watchSubscription) excluded(p string) bool {
+	for {
+		for _, pattern := range w.exclude {
+			if matchGlob(pattern, p) {
+				return true
+			}
+		}
+		p = path.Dir(p)
+		if p == w.path || p == "." || p == "/" {
+			return false
+		}
+	}
+}
+
+// covers reports whether the subscription watches directory dir.
+func (w *watchSubscription) covers(dir string) bool {
+	if dir == w.path {
+		return true
+	}
+	if !w.recursive {
+		return false
+	}
+	return w.path == "." || strings.HasPrefix(dir, w.path+"/")
+}
+
+// matchGlob matches a '/'-separated path against pattern. Components are
+// matched with path.Match; a "**" component matches zero or more components.
+func matchGlob(pattern, name string) bool {
+	return matchParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchParts(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchParts(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func validGlob(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	for _, part := range strings.Split(pattern, "/") {
+		if _, err := path.Match(part, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		// Unanchored patterns start with "**"
+		{"**/.git/**", ".git", true},
+		{"**/.git/**", ".git/HEAD", true},
+		{"**/.git/**", "src/.git/objects/ab", true},
+		{"**/.git/**", "src/git/HEAD", false},
+		{"**/*.log", "a.log", true},
+		{"**/*.log", "x/y/a.log", true},
+		{"**/*.log", "x/a.log/b", false},
+		// Anchored patterns match from the share root
+		{"*.log", "a.log", true},
+		{"*.log", "x/a.log", false},
+		{"build/*.o", "build/a.o", true},
+		{"build/*.o", "src/build/a.o", false},
+		// "*" does not cross '/'
+		{"a/*", "a/b", true},
+		{"a/*", "a/b/c", false},
+		{"*", "a/b", false},
+		{"a*c", "a/c", false},
+		// Trailing "**" matches the directory and everything below it
+		{"build/**", "build", true},
+		{"build/**", "build/a/b.o", true},
+		{"build/**", "builds/a", false},
+		// Middle "**" matches zero or more components
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"a/**/b", "x/a/b", false},
+		{"a/**/b/**/c", "a/1/b/2/3/c", true},
+		{"**", "anything/at/all", true},
+		{"?.txt", "a.txt", true},
+		{"[ab].txt", "c.txt", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWatchSubscriptionExcludesTrees(t *testing.T) {
+	w := &watchSubscription{path: "src", recursive: true, exclude: []string{"**/node_modules", "*.tmp"}}
+	tests := []struct {
+		path string
+		want bool // Reported
+	}{
+		{"src/main.go", true},
+		{"src/node_modules", false},
+		{"src/node_modules/pkg/index.js", false},
+		{"src/lib/node_modules/x", false},
+		// Anchored, so only top-level names match
+		{"src/a.tmp", true},
+		// Outside the subscription
+		{"other/file", false},
+	}
+	for _, tt := range tests {
+		if got := w.matchesPath(tt.path); got != tt.want {
+			t.Errorf("matchesPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -36,7 +36,7 @@ func main() {
 
 	// Test ReadDir
 	log.Printf("Testing ReadDir...")
-	resp, err := client.ReadDir(ctx, &pb.ReadDirRequest{Path: "", Offset: 0, Limit: 10})
+	resp, err := client.ReadDir(ctx, &pb.ReadDirRequest{Path: "", Limit: 10})
 	if err != nil {
 		log.Fatalf("ReadDir failed: %v", err)
 	}